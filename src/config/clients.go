@@ -0,0 +1,48 @@
+package config
+
+import (
+	"github.com/jotitan/fyne_poc/src/music"
+	"net/http"
+	"time"
+)
+
+func newClient(tlsConfig music.TLSConfig, endpoint string, timeout Duration, username, password string) (*http.Client, error) {
+	client, err := music.NewHTTPClient(tlsConfig, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = time.Duration(timeout)
+	return music.WithBasicAuth(client, username, password), nil
+}
+
+// Clients return http clients of server and player of profile
+func (p Profile) Clients(timeout Duration) (*http.Client, *http.Client, error) {
+	serverClient, err := newClient(p.ServerTLS, p.ServerURL, timeout, p.Username, p.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+	playerClient, err := newClient(p.PlayerTLS, p.PlayerURL, timeout, p.Username, p.Password)
+	return serverClient, playerClient, err
+}
+
+// RoomClient return http client of an additional player of profile
+func (p Profile) RoomClient(room Room, timeout Duration) (*http.Client, error) {
+	return newClient(room.TLS, room.URL, timeout, p.Username, p.Password)
+}
+
+// Pin accepts a certificate for the endpoint (server, player or room url) it was presented by, return false if no endpoint matches
+func (p *Profile) Pin(endpoint, fingerprint string) bool {
+	pinned := false
+	pin := func(url string, tlsConfig *music.TLSConfig) {
+		if url == endpoint {
+			tlsConfig.PinnedFingerprints = append(tlsConfig.PinnedFingerprints, fingerprint)
+			pinned = true
+		}
+	}
+	pin(p.ServerURL, &p.ServerTLS)
+	pin(p.PlayerURL, &p.PlayerTLS)
+	for i := range p.Rooms {
+		pin(p.Rooms[i].URL, &p.Rooms[i].TLS)
+	}
+	return pinned
+}
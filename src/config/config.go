@@ -0,0 +1,411 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jotitan/fyne_poc/src/music"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const appFolder = "music_client"
+const fileName = "config.json"
+const historyFileName = "history.jsonl"
+const ratingsFileName = "ratings.json"
+const playlistsFileName = "playlists.json"
+const defaultProfile = "default"
+
+// Duration is a time.Duration written as "10s" in configuration file
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// Profile is a named connection to a server and a player
+type Profile struct {
+	Name      string          `json:"name"`
+	ServerURL string          `json:"server_url"`
+	PlayerURL string          `json:"player_url"`
+	Username  string          `json:"username,omitempty"`
+	Password  string          `json:"password,omitempty"`
+	ServerTLS music.TLSConfig `json:"server_tls"`
+	PlayerTLS music.TLSConfig `json:"player_tls"`
+	// Other players which can be controlled with the same server
+	Rooms []Room `json:"rooms,omitempty"`
+}
+
+// Room is an additional player of a profile
+type Room struct {
+	Name string          `json:"name"`
+	URL  string          `json:"url"`
+	TLS  music.TLSConfig `json:"tls"`
+}
+
+// AutoDJ append tracks when fewer than MinRemaining tracks remain in queue
+type AutoDJ struct {
+	Enabled bool `json:"enabled"`
+	// artist, album or recent
+	Strategy     string `json:"strategy"`
+	MinRemaining int    `json:"min_remaining"`
+}
+
+// Strategies of auto DJ, in order of music.DJStrategy
+var DJStrategies = []string{"artist", "album", "recent"}
+
+func (a AutoDJ) DJStrategy() music.DJStrategy {
+	for i, name := range DJStrategies {
+		if name == a.Strategy {
+			return music.DJStrategy(i)
+		}
+	}
+	return music.SameArtistStrategy
+}
+
+type Config struct {
+	Profiles       []Profile `json:"profiles"`
+	CurrentProfile string    `json:"current_profile"`
+	Timeout        Duration  `json:"timeout"`
+	PollInterval   Duration  `json:"poll_interval"`
+	// Language of UI (fr, en), empty means system one
+	Language string `json:"language"`
+	// Theme : light or dark, empty means system one
+	Theme string `json:"theme"`
+	// No desktop notification when track changes
+	DisableNotifications bool   `json:"disable_notifications,omitempty"`
+	AutoDJ               AutoDJ `json:"auto_dj"`
+	// Key of actions by name, missing ones use DefaultShortcuts
+	Shortcuts map[string]string `json:"shortcuts,omitempty"`
+	// Minimal level of log file : debug, info, warn or error, empty means info
+	LogLevel string `json:"log_level,omitempty"`
+	// Endpoints given on command line, never saved
+	override *endpointOverride
+}
+
+// Urls of a profile replaced on command line
+type endpointOverride struct {
+	profile        string
+	server, player string
+	// Profile as in file, nil when it was created by the override
+	original *Profile
+}
+
+func Default() Config {
+	return Config{
+		Timeout:      Duration(10 * time.Second),
+		PollInterval: Duration(10 * time.Second),
+		AutoDJ:       AutoDJ{Strategy: DJStrategies[0], MinRemaining: 2},
+	}
+}
+
+// Path of a file of application in user config dir
+func userFile(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appFolder, name), nil
+}
+
+// DefaultPath return the path of configuration file in user config dir
+func DefaultPath() (string, error) {
+	return userFile(fileName)
+}
+
+// HistoryPath return the path of listening history, next to configuration file
+func HistoryPath() (string, error) {
+	return userFile(historyFileName)
+}
+
+// RatingsPath return the path of favorites and ratings, next to configuration file
+func RatingsPath() (string, error) {
+	return userFile(ratingsFileName)
+}
+
+// PlaylistsPath return the path of saved playlists, next to configuration file
+func PlaylistsPath() (string, error) {
+	return userFile(playlistsFileName)
+}
+
+// Current return the selected profile, the first one if none is selected
+func (c Config) Current() Profile {
+	for _, profile := range c.Profiles {
+		if profile.Name == c.CurrentProfile {
+			return profile
+		}
+	}
+	if len(c.Profiles) > 0 {
+		return c.Profiles[0]
+	}
+	return Profile{Name: defaultProfile}
+}
+
+func (c Config) hasProfile(name string) bool {
+	for _, profile := range c.Profiles {
+		if profile.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetProfile replace the profile with the same name or add it
+func (c *Config) SetProfile(profile Profile) {
+	for i, existing := range c.Profiles {
+		if existing.Name == profile.Name {
+			c.Profiles[i] = profile
+			return
+		}
+	}
+	c.Profiles = append(c.Profiles, profile)
+}
+
+func (c Config) ProfileNames() []string {
+	names := make([]string, len(c.Profiles))
+	for i, profile := range c.Profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// Load read configuration file. If file doesn't exist, default configuration is returned
+func Load(path string) (Config, error) {
+	conf := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return conf, nil
+	}
+	if err != nil {
+		return conf, err
+	}
+	if err = json.Unmarshal(data, &conf); err != nil {
+		return conf, err
+	}
+	// Files written before profiles only have a server and a player
+	var legacy struct {
+		ServerURL string          `json:"server_url"`
+		PlayerURL string          `json:"player_url"`
+		ServerTLS music.TLSConfig `json:"server_tls"`
+		PlayerTLS music.TLSConfig `json:"player_tls"`
+	}
+	if err = json.Unmarshal(data, &legacy); err == nil && len(conf.Profiles) == 0 && legacy.ServerURL != "" {
+		conf.SetProfile(Profile{
+			Name:      defaultProfile,
+			ServerURL: legacy.ServerURL,
+			PlayerURL: legacy.PlayerURL,
+			ServerTLS: legacy.ServerTLS,
+			PlayerTLS: legacy.PlayerTLS,
+		})
+		conf.CurrentProfile = defaultProfile
+	}
+	conf.clampDurations()
+	return conf, err
+}
+
+// Durations which are not positive fall back to default ones, tickers can't run with them
+func (c *Config) clampDurations() {
+	defaults := Default()
+	if c.Timeout <= 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaults.PollInterval
+	}
+}
+
+func (c Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c.withoutOverride(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func validateURL(name, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s url : %s", name, value)
+	}
+	return nil
+}
+
+func (p Profile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile must have a name")
+	}
+	if err := validateURL("server", p.ServerURL); err != nil {
+		return err
+	}
+	for _, room := range p.Rooms {
+		if err := validateURL(room.Name, room.URL); err != nil {
+			return err
+		}
+	}
+	return validateURL("player", p.PlayerURL)
+}
+
+// Validate check current profile and durations
+func (c Config) Validate() error {
+	if err := c.Current().Validate(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 || c.PollInterval <= 0 {
+		return errors.New("timeout and poll interval must be positive")
+	}
+	return nil
+}
+
+// Flags are the command line overrides of configuration
+type Flags struct {
+	path        *string
+	profileName *string
+	server      *string
+	player      *string
+	timeout     *time.Duration
+	poll        *time.Duration
+	language    *string
+	theme       *string
+}
+
+// RegisterFlags declare configuration flags on a flag set, Load must be called after parsing
+func RegisterFlags(flags *flag.FlagSet) *Flags {
+	return &Flags{
+		path:        flags.String("config", "", "path of configuration file"),
+		profileName: flags.String("profile", "", "name of profile to use"),
+		server:      flags.String("server", "", "url of music server"),
+		player:      flags.String("player", "", "url of music player"),
+		timeout:     flags.Duration("timeout", 0, "timeout of requests"),
+		poll:        flags.Duration("poll", 0, "interval between two refreshes of player state"),
+		language:    flags.String("lang", "", "language of interface (fr, en)"),
+		theme:       flags.String("theme", "", "theme (light, dark)"),
+	}
+}
+
+// Load read configuration file (default one or -config) and apply overrides.
+// Return configuration and path of the file
+func (f *Flags) Load() (Config, string, error) {
+	path := *f.path
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return Config{}, "", err
+		}
+		path = defaultPath
+	}
+	conf, err := Load(path)
+	if err != nil {
+		return conf, path, err
+	}
+	if *f.profileName != "" {
+		if !conf.hasProfile(*f.profileName) {
+			return conf, path, fmt.Errorf("unknown profile %s, existing ones : %s", *f.profileName, strings.Join(conf.ProfileNames(), ", "))
+		}
+		conf.CurrentProfile = *f.profileName
+	}
+	conf.overrideEndpoints(*f.server, *f.player)
+	if *f.timeout != 0 {
+		conf.Timeout = Duration(*f.timeout)
+	}
+	if *f.poll != 0 {
+		conf.PollInterval = Duration(*f.poll)
+	}
+	conf.clampDurations()
+	if *f.language != "" {
+		conf.Language = *f.language
+	}
+	if *f.theme != "" {
+		conf.Theme = *f.theme
+	}
+	return conf, path, nil
+}
+
+// Replace urls of current profile when not empty, in memory only
+func (c *Config) overrideEndpoints(server, player string) {
+	if server == "" && player == "" {
+		return
+	}
+	profile := c.Current()
+	override := endpointOverride{profile: profile.Name}
+	if c.override != nil && c.override.profile == profile.Name {
+		override = *c.override
+	} else if c.hasProfile(profile.Name) {
+		original := profile
+		override.original = &original
+	}
+	if server != "" {
+		profile.ServerURL, override.server = server, server
+	}
+	if player != "" {
+		profile.PlayerURL, override.player = player, player
+	}
+	c.SetProfile(profile)
+	c.CurrentProfile = profile.Name
+	c.override = &override
+}
+
+// Urls given on command line are replaced by the ones of file, unless they were changed since
+func (c Config) withoutOverride() Config {
+	if c.override == nil {
+		return c
+	}
+	original := Profile{}
+	if c.override.original != nil {
+		original = *c.override.original
+	}
+	profiles := make([]Profile, 0, len(c.Profiles))
+	for _, profile := range c.Profiles {
+		if profile.Name == c.override.profile {
+			if c.override.server != "" && profile.ServerURL == c.override.server {
+				profile.ServerURL = original.ServerURL
+			}
+			if c.override.player != "" && profile.PlayerURL == c.override.player {
+				profile.PlayerURL = original.PlayerURL
+			}
+			// Profile only known from command line
+			if c.override.original == nil && profile.ServerURL == "" && profile.PlayerURL == "" {
+				continue
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	c.Profiles = profiles
+	return c
+}
+
+// FromArgs load configuration file (default one or -config) and apply command line overrides.
+// Return configuration and path of the file
+func FromArgs(args []string) (Config, string, error) {
+	flags := flag.NewFlagSet("music", flag.ContinueOnError)
+	overrides := RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return Config{}, "", err
+	}
+	conf, path, err := overrides.Load()
+	// Keep old behaviour : server and player as positional arguments
+	if err == nil && flags.NArg() >= 2 {
+		conf.overrideEndpoints(flags.Arg(0), flags.Arg(1))
+		conf.overrideEndpoints(*overrides.server, *overrides.player)
+	}
+	return conf, path, err
+}
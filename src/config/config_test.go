@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.json")
+	conf := Default()
+	conf.SetProfile(Profile{Name: "salon", ServerURL: "http://server:9000", PlayerURL: "http://player:9001"})
+	conf.PollInterval = Duration(5 * time.Second)
+	if err := conf.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Current().ServerURL != "http://server:9000" || loaded.PollInterval != conf.PollInterval {
+		t.Error("bad loaded configuration", loaded)
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	conf := Default()
+	conf.SetProfile(Profile{Name: "salon", ServerURL: "http://server:9000", PlayerURL: "http://player:9001"})
+	conf.SetProfile(Profile{Name: "bureau", ServerURL: "http://server:9000", PlayerURL: "http://office:9001"})
+	conf.Save(path)
+
+	loaded, loadedPath, err := FromArgs([]string{"-config", path, "-profile", "bureau", "-player", "http://other:9001", "-timeout", "3s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := loaded.Current()
+	if loadedPath != path || current.Name != "bureau" || current.PlayerURL != "http://other:9001" || loaded.Timeout != Duration(3*time.Second) {
+		t.Error("overrides not applied", loaded)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	// Overridden player isn't saved, other changes are
+	loaded.Theme = "dark"
+	if err := loaded.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := Load(path)
+	if saved.Current().PlayerURL != "http://office:9001" || saved.Theme != "dark" {
+		t.Error("overrides must not be saved", saved)
+	}
+
+	if _, _, err := FromArgs([]string{"-config", path, "-profile", "cuisine"}); err == nil {
+		t.Error("unknown profile must be refused")
+	}
+}
+
+func TestOverrideWithoutFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	conf, _, err := FromArgs([]string{"-config", path, "http://server:9000", "http://player:9001"})
+	if err != nil || conf.Current().PlayerURL != "http://player:9001" {
+		t.Fatal("positional endpoints must be used", conf, err)
+	}
+	conf.Save(path)
+	if saved, _ := Load(path); len(saved.Profiles) != 0 {
+		t.Error("profile only given on command line must not be saved", saved.Profiles)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	conf := Default()
+	conf.SetProfile(Profile{Name: "salon", ServerURL: "server:9000", PlayerURL: "http://player:9001"})
+	if conf.Validate() == nil {
+		t.Error("url without scheme must be rejected")
+	}
+	conf.SetProfile(Profile{Name: "salon", ServerURL: "http://server:9000", PlayerURL: "http://player:9001"})
+	conf.LogLevel = "loud"
+	if err := conf.Validate(); err != nil {
+		t.Error("bad log level is only a warning", err)
+	}
+}
+
+func TestLoadLegacyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"server_url":"http://server:9000","player_url":"http://player:9001","timeout":"5s"}`), 0600)
+	conf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current := conf.Current(); current.Name != "default" || current.PlayerURL != "http://player:9001" {
+		t.Error("legacy file must be migrated to a profile", conf)
+	}
+}
+
+func TestDurationsFallBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"timeout":"0s","poll_interval":"-5s"}`), 0600)
+	conf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Timeout != Default().Timeout || conf.PollInterval != Default().PollInterval {
+		t.Error("durations must fall back to default ones", conf.Timeout, conf.PollInterval)
+	}
+	conf, _, err = FromArgs([]string{"-config", path, "-poll", "-1s"})
+	if err != nil || conf.PollInterval != Default().PollInterval {
+		t.Error("negative poll must fall back to default one", conf.PollInterval, err)
+	}
+}
+
+func TestBindings(t *testing.T) {
+	conf := Default()
+	conf.Shortcuts = map[string]string{ActionNext: "ctrl+shift+n", ActionVolumeUp: "Alt++", ActionAdd: "Enter"}
+	bindings, err := conf.Bindings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := bindings[ActionNext]; !next.Ctrl || !next.Shift || next.Key != "N" {
+		t.Error("bad next shortcut", next)
+	}
+	if up := bindings[ActionVolumeUp]; !up.Alt || up.Key != "+" || !up.IsCharacter() {
+		t.Error("bad volume shortcut", up)
+	}
+	if add := bindings[ActionAdd]; add.Key != "Return" || add.IsCharacter() {
+		t.Error("enter must be an alias of return", add)
+	}
+	if pause := bindings[ActionPause]; pause.Key != "Space" {
+		t.Error("missing shortcut must use default one", pause)
+	}
+
+	conf.Shortcuts = map[string]string{ActionPause: "Hyper+P", "dance": "D"}
+	bindings, err = conf.Bindings()
+	if err == nil {
+		t.Error("bad modifier and unknown action must be reported")
+	}
+	if pause := bindings[ActionPause]; pause.Key != "Space" {
+		t.Error("invalid shortcut must fallback on default one", pause)
+	}
+}
+
+func TestAutoDJStrategy(t *testing.T) {
+	if strategy := Default().AutoDJ.DJStrategy(); strategy != music.SameArtistStrategy {
+		t.Error("default strategy must be same artist", strategy)
+	}
+	if strategy := (AutoDJ{Strategy: "recent"}).DJStrategy(); strategy != music.RecentArtistsStrategy {
+		t.Error("bad strategy", strategy)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	conf := Default()
+	if level, err := conf.Level(); err != nil || level != slog.LevelInfo {
+		t.Error("default level must be info", level, err)
+	}
+	conf.LogLevel = "loud"
+	if _, err := conf.Level(); err == nil {
+		t.Error("bad level must be refused")
+	}
+	defer slog.SetDefault(slog.Default())
+	path := filepath.Join(t.TempDir(), "logs", "client.log")
+	closer, err := StartLogging(path, slog.LevelWarn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.Info("hidden")
+	slog.Warn("player unreachable", "url", "http://player:9001")
+	closer.Close()
+	data, _ := os.ReadFile(path)
+	if content := string(data); strings.Contains(content, "hidden") || !strings.Contains(content, "url=http://player:9001") {
+		t.Error("bad log file", content)
+	}
+}
+
+func TestPinRoomCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	profile := Profile{Name: "home", ServerURL: "https://server:9000", PlayerURL: "https://player:9001",
+		Rooms: []Room{{Name: "kitchen", URL: server.URL}}}
+
+	client, _ := profile.RoomClient(profile.Rooms[0], Default().Timeout)
+	_, err := client.Get(server.URL)
+	var untrusted *music.UntrustedCertificateError
+	if !errors.As(err, &untrusted) {
+		t.Fatalf("must be untrusted, got %v", err)
+	}
+	if !profile.Pin(untrusted.Endpoint, untrusted.Fingerprint) {
+		t.Fatal("room endpoint must match", untrusted.Endpoint)
+	}
+	if len(profile.ServerTLS.PinnedFingerprints) != 0 || len(profile.PlayerTLS.PinnedFingerprints) != 0 {
+		t.Error("only room must be pinned")
+	}
+	client, _ = profile.RoomClient(profile.Rooms[0], Default().Timeout)
+	if _, err = client.Get(server.URL); err != nil {
+		t.Error("pinned room must be trusted", err)
+	}
+	if profile.Pin("https://other:9000", untrusted.Fingerprint) {
+		t.Error("unknown endpoint must not match")
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const logFileName = "music_client.log"
+
+// LogPath return the path of log file in user cache dir
+func LogPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appFolder, logFileName), nil
+}
+
+// Level of log file, info when not set
+func (c Config) Level() (slog.Level, error) {
+	var level slog.Level
+	if c.LogLevel == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("bad log level %q, use debug, info, warn or error", c.LogLevel)
+	}
+	return level, nil
+}
+
+// StartLogging send default logger to file at path (appended) and to stderr. Returned closer closes the file
+func StartLogging(path string, level slog.Level) (io.Closer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	handler := slog.NewTextHandler(io.MultiWriter(file, os.Stderr), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+	return file, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Actions which can be bound to a key
+const (
+	ActionPause       = "pause"
+	ActionNext        = "next"
+	ActionPrevious    = "previous"
+	ActionVolumeUp    = "volume_up"
+	ActionVolumeDown  = "volume_down"
+	ActionSearch      = "search"
+	ActionAdd         = "add"
+	ActionRemove      = "remove"
+	ActionCloseSearch = "close_search"
+	ActionUndo        = "undo"
+	ActionRedo        = "redo"
+)
+
+// DefaultShortcuts return key of each action, overridden by shortcuts of configuration file
+func DefaultShortcuts() map[string]string {
+	return map[string]string{
+		ActionPause:       "Space",
+		ActionNext:        "N",
+		ActionPrevious:    "P",
+		ActionVolumeUp:    "+",
+		ActionVolumeDown:  "-",
+		ActionSearch:      "Ctrl+F",
+		ActionAdd:         "Return",
+		ActionRemove:      "Delete",
+		ActionCloseSearch: "Escape",
+		ActionUndo:        "Ctrl+Z",
+		ActionRedo:        "Ctrl+Y",
+	}
+}
+
+// KeyBinding is a parsed shortcut like "Ctrl+F". Key is a single uppercase character or a key name (Space, Return, F1...)
+type KeyBinding struct {
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Key   string
+}
+
+// IsCharacter is true when key is a typed character, false for named keys
+func (k KeyBinding) IsCharacter() bool {
+	return utf8.RuneCountInString(k.Key) == 1
+}
+
+// ParseKeyBinding read a shortcut written as modifiers and key separated by +, like "Ctrl+Shift+F" or "+"
+func ParseKeyBinding(value string) (KeyBinding, error) {
+	binding := KeyBinding{}
+	if value == "" {
+		return binding, errors.New("empty shortcut")
+	}
+	// Last part is the key, it can be + itself
+	separator := strings.LastIndex(value[:len(value)-1], "+")
+	key := value[separator+1:]
+	if separator >= 0 {
+		for _, modifier := range strings.Split(value[:separator], "+") {
+			switch strings.ToLower(strings.TrimSpace(modifier)) {
+			case "ctrl", "control":
+				binding.Ctrl = true
+			case "alt":
+				binding.Alt = true
+			case "shift":
+				binding.Shift = true
+			default:
+				return binding, fmt.Errorf("unknown modifier %s in shortcut %s", modifier, value)
+			}
+		}
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return binding, fmt.Errorf("shortcut %s has no key", value)
+	}
+	if utf8.RuneCountInString(key) == 1 {
+		key = strings.ToUpper(key)
+	} else if strings.EqualFold(key, "enter") {
+		key = "Return"
+	}
+	binding.Key = key
+	return binding, nil
+}
+
+// Bindings return parsed shortcuts of all actions. Invalid shortcuts are replaced by default ones and reported in error
+func (c Config) Bindings() (map[string]KeyBinding, error) {
+	shortcuts := DefaultShortcuts()
+	for action, value := range c.Shortcuts {
+		shortcuts[action] = value
+	}
+	defaults := DefaultShortcuts()
+	bindings := make(map[string]KeyBinding, len(shortcuts))
+	var invalids []string
+	for action, value := range shortcuts {
+		if _, exist := defaults[action]; !exist {
+			invalids = append(invalids, fmt.Sprintf("unknown action %s", action))
+			continue
+		}
+		binding, err := ParseKeyBinding(value)
+		if err != nil {
+			invalids = append(invalids, err.Error())
+			binding, _ = ParseKeyBinding(defaults[action])
+		}
+		bindings[action] = binding
+	}
+	if len(invalids) > 0 {
+		sort.Strings(invalids)
+		return bindings, fmt.Errorf("invalid shortcuts : %s", strings.Join(invalids, ", "))
+	}
+	return bindings, nil
+}
@@ -0,0 +1,191 @@
+package i18n
+
+var enMessages = map[string]string{
+	"window.title": "Music player",
+	"window.room":  "Music player - %s",
+	"date.format":  "01/02/2006",
+
+	"action.add":     "Add",
+	"action.add_all": "Add all",
+	"action.show":    "Show",
+	"action.save":    "Save",
+	"action.cancel":  "Cancel",
+	"action.delete":  "Delete",
+	"action.refresh": "Refresh",
+	"action.retry":   "Retry",
+	"action.close":   "Close",
+	"action.play":    "Play",
+
+	"menu.profiles": "Profiles",
+	"menu.views":    "View",
+
+	"error.add":        "Can't add",
+	"error.play":       "Can't play",
+	"error.connection": "Can't connect",
+	"error.load":       "Can't load",
+	"error.search":     "Search failed",
+	"error.queue":      "Can't change queue",
+	"error.rating":     "Rating not saved",
+	"error.refused":    "Command refused by player",
+	"error.save":       "Can't save",
+
+	"certificate.title":   "Unknown certificate",
+	"certificate.message": "Unknown certificate for %s\n%s\n%s\n\nTrust this certificate?",
+
+	"search.title":       "Add music",
+	"search.placeholder": "Search...",
+
+	"nowplaying.nothing": "Nothing playing",
+
+	"browser.search":  "Search",
+	"index.title":     "A–Z index",
+	"index.artists":   "Artists",
+	"index.albums":    "Albums",
+	"browser.loading": "Loading...",
+
+	"health.connected": "Connected (%d ms)",
+	"health.degraded":  "Degraded (%d ms)",
+	"health.offline":   "Offline",
+
+	"queue.conflict.title":   "Queue changed",
+	"queue.conflict.message": "Queue was changed on player, nothing was done",
+	"queue.clear.title":      "Clear queue",
+	"queue.clear.message":    "Remove all tracks from queue?",
+	"queue.clear.error":      "Can't clear queue",
+	"queue.move.unsupported": "The player can't move tracks",
+
+	"journal.error":    "Can't undo",
+	"journal.undone":   "Undo",
+	"journal.lost":     "Removed tracks are back at the end of the queue, the player can't put them back in place",
+	"journal.diverged": "Queue was changed on player, the %s can't be undone anymore",
+	"journal.add":      "addition",
+	"journal.delete":   "removal",
+	"journal.move":     "move",
+	"journal.clear":    "clearing",
+
+	"selection.remove": "Remove",
+	"selection.top":    "To top",
+	"selection.next":   "Play next",
+	"selection.save":   "Save selection",
+
+	"playlists.title": "Playlists",
+	"playlists.name":  "Playlist name",
+
+	"favorites.title": "Favorites",
+
+	"history.title":    "History",
+	"history.from":     "From",
+	"history.to":       "to",
+	"history.filter":   "Filter",
+	"history.bad_date": "Bad date, use format %s",
+
+	"stats.title":       "Statistics",
+	"stats.week":        "Week",
+	"stats.month":       "Month",
+	"stats.year":        "Year",
+	"stats.artists":     "Artists",
+	"stats.albums":      "Albums",
+	"stats.tracks":      "Tracks",
+	"stats.export_csv":  "Export CSV",
+	"stats.export_json": "Export JSON",
+
+	"day.monday":    "Mon",
+	"day.tuesday":   "Tue",
+	"day.wednesday": "Wed",
+	"day.thursday":  "Thu",
+	"day.friday":    "Fri",
+	"day.saturday":  "Sat",
+	"day.sunday":    "Sun",
+
+	"radio.title":        "Radio",
+	"radio.artists":      "Artists",
+	"radio.albums":       "Albums",
+	"radio.excluded":     "Excluded artists",
+	"radio.years":        "Years",
+	"radio.genres":       "Genres",
+	"radio.seed":         "Seed",
+	"radio.all":          "All, separated by commas",
+	"radio.random":       "Random",
+	"radio.start":        "Start",
+	"radio.stop":         "Stop",
+	"radio.stopped":      "Stopped",
+	"radio.playing":      "Playing",
+	"radio.playing_seed": "Playing (seed %d)",
+	"radio.bad_numbers":  "Years and seed must be numbers",
+
+	"rooms.title":       "Rooms",
+	"rooms.all":         "All rooms",
+	"rooms.main":        "Main",
+	"rooms.unreachable": "Unreachable",
+	"rooms.controlled":  "%s (controlled)",
+	"rooms.control":     "Control",
+	"rooms.transfer":    "Transfer here",
+	"rooms.selection":   "Selection:",
+	"rooms.pause":       "Pause",
+	"rooms.play":        "Play",
+	"rooms.volume_down": "Volume -",
+	"rooms.volume_up":   "Volume +",
+
+	"settings.title":         "Settings",
+	"settings.profile":       "Profile",
+	"settings.server":        "Server",
+	"settings.player":        "Player",
+	"settings.username":      "Username",
+	"settings.password":      "Password",
+	"settings.timeout":       "Timeout",
+	"settings.poll":          "Refresh",
+	"settings.language":      "Language",
+	"settings.theme":         "Theme",
+	"settings.notifications": "Notifications",
+	"settings.track_change":  "Track change",
+	"settings.auto_dj":       "Auto DJ",
+	"settings.fill_queue":    "Fill queue",
+	"settings.min_remaining": "Remaining tracks",
+	"settings.bad_remaining": "Remaining tracks must be a positive number",
+	"settings.bad_duration":  "Bad duration, use format like 10s",
+
+	"language.system": "System",
+	"language.fr":     "Français",
+	"language.en":     "English",
+	"theme.system":    "System",
+	"theme.light":     "Light",
+	"theme.dark":      "Dark",
+
+	"dj.same_artist":    "Same artist",
+	"dj.same_album":     "Same album",
+	"dj.recent_artists": "Recent artists",
+
+	"discovery.search":        "Search on network",
+	"discovery.searching":     "Searching...",
+	"discovery.servers_found": "Servers found",
+	"discovery.players_found": "Players found",
+	"discovery.found":         "%s, %s",
+
+	"tui.queue":       "Queue",
+	"tui.search":      "Search: %s",
+	"tui.back":        "(Esc: back)",
+	"tui.error":       "Error: %s",
+	"tui.added":       "Added: %s",
+	"tui.removed":     "Removed",
+	"tui.playing":     "Playing",
+	"tui.paused":      "Paused",
+	"tui.next":        "Next",
+	"tui.previous":    "Previous",
+	"tui.volume_up":   "Volume +",
+	"tui.volume_down": "Volume -",
+	"tui.help.queue":  "Enter play  Del remove  Space pause  n/p next/previous  +/- volume  Tab// search  q quit",
+	"tui.help.search": "Enter add/open  Ctrl+A add all  Esc back  Tab queue  Ctrl+C quit",
+}
+
+var enPlurals = map[string][2]string{
+	"outbox.pending":    {"%d command waiting for player", "%d commands waiting for player"},
+	"selection.count":   {"%d selected", "%d selected"},
+	"playlists.line":    {"%[2]s (%[1]d track)", "%[2]s (%[1]d tracks)"},
+	"rooms.idle":        {"Nothing playing (%d track)", "Nothing playing (%d tracks)"},
+	"stats.summary":     {"From %[2]s to %[3]s: %[1]d track, %[4]s", "From %[2]s to %[3]s: %[1]d tracks, %[4]s"},
+	"discovery.servers": {"%d server", "%d servers"},
+	"discovery.players": {"%d player", "%d players"},
+	"tracks.added":      {"%d track added", "%d tracks added"},
+	"browser.albums":    {"%d album", "%d albums"},
+	"browser.tracks":    {"%d track", "%d tracks"},
+}
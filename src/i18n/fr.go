@@ -0,0 +1,191 @@
+package i18n
+
+var frMessages = map[string]string{
+	"window.title": "Music player",
+	"window.room":  "Music player - %s",
+	"date.format":  "02/01/2006",
+
+	"action.add":     "Ajouter",
+	"action.add_all": "Tout ajouter",
+	"action.show":    "Voir",
+	"action.save":    "Enregistrer",
+	"action.cancel":  "Annuler",
+	"action.delete":  "Supprimer",
+	"action.refresh": "Actualiser",
+	"action.retry":   "Réessayer",
+	"action.close":   "Fermer",
+	"action.play":    "Lire",
+
+	"menu.profiles": "Profils",
+	"menu.views":    "Affichage",
+
+	"error.add":        "Ajout impossible",
+	"error.play":       "Lecture impossible",
+	"error.connection": "Connexion impossible",
+	"error.load":       "Chargement impossible",
+	"error.search":     "Recherche impossible",
+	"error.queue":      "Modification de la file impossible",
+	"error.rating":     "Note non enregistrée",
+	"error.refused":    "Commande refusée par le lecteur",
+	"error.save":       "Enregistrement impossible",
+
+	"certificate.title":   "Certificat inconnu",
+	"certificate.message": "Certificat non reconnu pour %s\n%s\n%s\n\nFaire confiance à ce certificat ?",
+
+	"search.title":       "Ajouter musique",
+	"search.placeholder": "Rechercher...",
+
+	"nowplaying.nothing": "Rien en cours",
+
+	"browser.search":  "Recherche",
+	"index.title":     "Index A–Z",
+	"index.artists":   "Artistes",
+	"index.albums":    "Albums",
+	"browser.loading": "Chargement...",
+
+	"health.connected": "Connecté (%d ms)",
+	"health.degraded":  "Dégradé (%d ms)",
+	"health.offline":   "Hors ligne",
+
+	"queue.conflict.title":   "File modifiée",
+	"queue.conflict.message": "La file a été modifiée sur le lecteur, rien n'a été changé",
+	"queue.clear.title":      "Vider la file",
+	"queue.clear.message":    "Retirer tous les titres de la file ?",
+	"queue.clear.error":      "Impossible de vider la file",
+	"queue.move.unsupported": "Le lecteur ne permet pas de déplacer les titres",
+
+	"journal.error":    "Annulation impossible",
+	"journal.undone":   "Annulation",
+	"journal.lost":     "Les titres supprimés sont revenus en fin de file, le lecteur ne permet pas de les remettre à leur place",
+	"journal.diverged": "La file a été modifiée sur le lecteur, l'opération %s ne peut plus être annulée",
+	"journal.add":      "d'ajout",
+	"journal.delete":   "de suppression",
+	"journal.move":     "de déplacement",
+	"journal.clear":    "de vidage",
+
+	"selection.remove": "Retirer",
+	"selection.top":    "En haut",
+	"selection.next":   "Ensuite",
+	"selection.save":   "Enregistrer la sélection",
+
+	"playlists.title": "Playlists",
+	"playlists.name":  "Nom de la playlist",
+
+	"favorites.title": "Favoris",
+
+	"history.title":    "Historique",
+	"history.from":     "Du",
+	"history.to":       "au",
+	"history.filter":   "Filtrer",
+	"history.bad_date": "Date invalide, utiliser le format %s",
+
+	"stats.title":       "Statistiques",
+	"stats.week":        "Semaine",
+	"stats.month":       "Mois",
+	"stats.year":        "Année",
+	"stats.artists":     "Artistes",
+	"stats.albums":      "Albums",
+	"stats.tracks":      "Titres",
+	"stats.export_csv":  "Exporter CSV",
+	"stats.export_json": "Exporter JSON",
+
+	"day.monday":    "Lun",
+	"day.tuesday":   "Mar",
+	"day.wednesday": "Mer",
+	"day.thursday":  "Jeu",
+	"day.friday":    "Ven",
+	"day.saturday":  "Sam",
+	"day.sunday":    "Dim",
+
+	"radio.title":        "Radio",
+	"radio.artists":      "Artistes",
+	"radio.albums":       "Albums",
+	"radio.excluded":     "Artistes exclus",
+	"radio.years":        "Années",
+	"radio.genres":       "Genres",
+	"radio.seed":         "Graine",
+	"radio.all":          "Tous, séparés par des virgules",
+	"radio.random":       "Aléatoire",
+	"radio.start":        "Démarrer",
+	"radio.stop":         "Arrêter",
+	"radio.stopped":      "Arrêtée",
+	"radio.playing":      "En cours",
+	"radio.playing_seed": "En cours (graine %d)",
+	"radio.bad_numbers":  "Les années et la graine doivent être des nombres",
+
+	"rooms.title":       "Pièces",
+	"rooms.all":         "Toutes les pièces",
+	"rooms.main":        "Principal",
+	"rooms.unreachable": "Injoignable",
+	"rooms.controlled":  "%s (contrôlée)",
+	"rooms.control":     "Contrôler",
+	"rooms.transfer":    "Transférer ici",
+	"rooms.selection":   "Sélection :",
+	"rooms.pause":       "Pause",
+	"rooms.play":        "Lecture",
+	"rooms.volume_down": "Volume -",
+	"rooms.volume_up":   "Volume +",
+
+	"settings.title":         "Paramètres",
+	"settings.profile":       "Profil",
+	"settings.server":        "Serveur",
+	"settings.player":        "Lecteur",
+	"settings.username":      "Utilisateur",
+	"settings.password":      "Mot de passe",
+	"settings.timeout":       "Délai d'attente",
+	"settings.poll":          "Rafraîchissement",
+	"settings.language":      "Langue",
+	"settings.theme":         "Thème",
+	"settings.notifications": "Notifications",
+	"settings.track_change":  "Changement de titre",
+	"settings.auto_dj":       "Auto DJ",
+	"settings.fill_queue":    "Compléter la file",
+	"settings.min_remaining": "Titres restants",
+	"settings.bad_remaining": "Le nombre de titres restants doit être positif",
+	"settings.bad_duration":  "Durée invalide, utiliser un format comme 10s",
+
+	"language.system": "Système",
+	"language.fr":     "Français",
+	"language.en":     "English",
+	"theme.system":    "Système",
+	"theme.light":     "Clair",
+	"theme.dark":      "Sombre",
+
+	"dj.same_artist":    "Même artiste",
+	"dj.same_album":     "Même album",
+	"dj.recent_artists": "Artistes récents",
+
+	"discovery.search":        "Rechercher sur le réseau",
+	"discovery.searching":     "Recherche...",
+	"discovery.servers_found": "Serveurs trouvés",
+	"discovery.players_found": "Lecteurs trouvés",
+	"discovery.found":         "%s, %s",
+
+	"tui.queue":       "File d'attente",
+	"tui.search":      "Recherche : %s",
+	"tui.back":        "(Échap : retour)",
+	"tui.error":       "Erreur : %s",
+	"tui.added":       "Ajouté : %s",
+	"tui.removed":     "Supprimé",
+	"tui.playing":     "Lecture",
+	"tui.paused":      "Pause",
+	"tui.next":        "Suivant",
+	"tui.previous":    "Précédent",
+	"tui.volume_up":   "Volume +",
+	"tui.volume_down": "Volume -",
+	"tui.help.queue":  "Entrée jouer  Suppr retirer  Espace pause  n/p suivant/précédent  +/- volume  Tab// recherche  q quitter",
+	"tui.help.search": "Entrée ajouter/ouvrir  Ctrl+A tout ajouter  Échap retour  Tab file d'attente  Ctrl+C quitter",
+}
+
+var frPlurals = map[string][2]string{
+	"outbox.pending":    {"%d commande en attente du lecteur", "%d commandes en attente du lecteur"},
+	"selection.count":   {"%d sélectionné", "%d sélectionnés"},
+	"playlists.line":    {"%[2]s (%[1]d titre)", "%[2]s (%[1]d titres)"},
+	"rooms.idle":        {"Rien en cours (%d titre)", "Rien en cours (%d titres)"},
+	"stats.summary":     {"Du %[2]s au %[3]s : %[1]d titre, %[4]s", "Du %[2]s au %[3]s : %[1]d titres, %[4]s"},
+	"discovery.servers": {"%d serveur", "%d serveurs"},
+	"discovery.players": {"%d lecteur", "%d lecteurs"},
+	"tracks.added":      {"%d titre ajouté", "%d titres ajoutés"},
+	"browser.albums":    {"%d album", "%d albums"},
+	"browser.tracks":    {"%d titre", "%d titres"},
+}
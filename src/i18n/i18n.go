@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Languages with a catalog, the first one is used when system language has none
+var Languages = []string{"fr", "en"}
+
+type catalog struct {
+	messages map[string]string
+	// Singular and plural forms, count is the first argument
+	plurals map[string][2]string
+	// True when count takes singular form
+	singular func(count int) bool
+}
+
+var catalogs = map[string]catalog{
+	"fr": {messages: frMessages, plurals: frPlurals, singular: func(count int) bool { return count == 0 || count == 1 || count == -1 }},
+	"en": {messages: enMessages, plurals: enPlurals, singular: func(count int) bool { return count == 1 || count == -1 }},
+}
+
+var locker sync.RWMutex
+var current = Languages[0]
+
+// Detect return configured language if it has a catalog, otherwise the one of system locale
+func Detect(configured string) string {
+	if _, exist := catalogs[configured]; exist {
+		return configured
+	}
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		// Like fr_FR.UTF-8
+		value := strings.ToLower(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		if language := value[:min(2, len(value))]; catalogs[language].messages != nil {
+			return language
+		}
+		// First variable set wins, like gettext
+		break
+	}
+	return Languages[0]
+}
+
+// SetLanguage select catalog from configured language, empty means system one. Return selected language
+func SetLanguage(configured string) string {
+	language := Detect(configured)
+	locker.Lock()
+	defer locker.Unlock()
+	current = language
+	return language
+}
+
+func Language() string {
+	locker.RLock()
+	defer locker.RUnlock()
+	return current
+}
+
+func currentCatalog() catalog {
+	return catalogs[Language()]
+}
+
+// T return message of key formatted with args. A key missing in catalog falls back to first language, then to key itself
+func T(key string, args ...interface{}) string {
+	format, exist := currentCatalog().messages[key]
+	if !exist {
+		if format, exist = catalogs[Languages[0]].messages[key]; !exist {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// N return singular or plural form of key depending on count, formatted with count then args
+func N(key string, count int, args ...interface{}) string {
+	c := currentCatalog()
+	forms, exist := c.plurals[key]
+	if !exist {
+		c = catalogs[Languages[0]]
+		if forms, exist = c.plurals[key]; !exist {
+			return key
+		}
+	}
+	format := forms[1]
+	if c.singular(count) {
+		format = forms[0]
+	}
+	return fmt.Sprintf(format, append([]interface{}{count}, args...)...)
+}
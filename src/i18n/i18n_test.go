@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func withLanguage(t *testing.T, language string) {
+	previous := Language()
+	SetLanguage(language)
+	t.Cleanup(func() { SetLanguage(previous) })
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	reference := catalogs[Languages[0]]
+	for _, language := range Languages[1:] {
+		c := catalogs[language]
+		if len(c.messages) != len(reference.messages) || len(c.plurals) != len(reference.plurals) {
+			t.Error("catalogs don't have the same size", language)
+		}
+		for key, format := range reference.messages {
+			if translated, exist := c.messages[key]; !exist {
+				t.Error("missing message", language, key)
+			} else if strings.Count(translated, "%") != strings.Count(format, "%") {
+				t.Error("arguments don't match", language, key)
+			}
+		}
+		for key, forms := range reference.plurals {
+			if translated, exist := c.plurals[key]; !exist {
+				t.Error("missing plural", language, key)
+			} else if strings.Count(translated[1], "%") != strings.Count(forms[1], "%") {
+				t.Error("arguments don't match", language, key)
+			}
+		}
+	}
+}
+
+// Keys used by panel and terminal UI must be in catalog
+func TestKeysOfInterfaces(t *testing.T) {
+	files, _ := filepath.Glob(filepath.Join("..", "panel", "*.go"))
+	tuiFiles, _ := filepath.Glob(filepath.Join("..", "tui", "*.go"))
+	files = append(files, tuiFiles...)
+	if len(files) == 0 {
+		t.Skip("no interface sources")
+	}
+	call := regexp.MustCompile(`i18n\.(T|N)\("([a-z_.]+)"[,)]`)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range call.FindAllStringSubmatch(string(data), -1) {
+			_, message := frMessages[match[2]]
+			_, plural := frPlurals[match[2]]
+			if (match[1] == "T" && !message) || (match[1] == "N" && !plural) {
+				t.Error("unknown key", file, match[2])
+			}
+		}
+	}
+}
+
+func TestPlurals(t *testing.T) {
+	withLanguage(t, "fr")
+	for count, expected := range map[int]string{0: "0 titre ajouté", 1: "1 titre ajouté", 12: "12 titres ajoutés"} {
+		if text := N("tracks.added", count); text != expected {
+			t.Error("bad french plural", count, text)
+		}
+	}
+	if text := N("playlists.line", 3, "rock"); text != "rock (3 titres)" {
+		t.Error("bad arguments order", text)
+	}
+	SetLanguage("en")
+	for count, expected := range map[int]string{0: "0 tracks added", 1: "1 track added", 12: "12 tracks added"} {
+		if text := N("tracks.added", count); text != expected {
+			t.Error("bad english plural", count, text)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	withLanguage(t, "en")
+	if text := T("rooms.controlled", "Salon"); text != "Salon (controlled)" {
+		t.Error("bad message", text)
+	}
+	if text := T("unknown.key"); text != "unknown.key" {
+		t.Error("unknown key must be returned as is", text)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if language := Detect(""); language != "en" {
+		t.Error("system language must be used", language)
+	}
+	if language := Detect("fr"); language != "fr" {
+		t.Error("configured language must win", language)
+	}
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	if language := Detect(""); language != Languages[0] {
+		t.Error("unknown language must use default one", language)
+	}
+	t.Setenv("LC_ALL", "C")
+	if language := Detect("it"); language != Languages[0] {
+		t.Error("unknown language must use default one", language)
+	}
+}
@@ -1,20 +1,69 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"fyne.io/fyne"
 	"fyne.io/fyne/app"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/mpris"
 	"github.com/jotitan/fyne_poc/src/panel"
+	"io"
+	"log/slog"
 	"os"
+	"time"
 )
 
 func main() {
+	conf, confPath, err := config.FromArgs(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configuration:", err)
+		os.Exit(2)
+	}
 	application := app.New()
-	win := application.NewWindow("Music player")
-
+	i18n.SetLanguage(conf.Language)
+	win := application.NewWindow(i18n.T("window.title"))
 	win.Resize(fyne.Size{800, 600})
-	mp := panel.NewMusicPanel(os.Args[1], os.Args[2], application)
+	if logs, err := startLogging(conf); err != nil {
+		slog.Warn("no log file", "err", err)
+	} else {
+		defer logs.Close()
+	}
+	mp, err := panel.NewMusicPanel(conf, confPath, application)
+	if err != nil {
+		panic(err)
+	}
+
+	// Desktop media controls, only when a session bus exists. They share pause state with panel
+	if server, err := mpris.Start(func() mpris.Player { return mp.Wrapper() }, mp, time.Duration(conf.PollInterval), nil); err != nil {
+		slog.Info("MPRIS not available", "err", err)
+	} else {
+		mp.OnPauseChanged(server.Refresh)
+	}
 
 	mp.CreateMainPanel(win)
+	if err := conf.Validate(); err != nil {
+		// First launch or bad configuration, ask user to fill settings
+		mp.ShowSettings()
+	}
 
 	win.ShowAndRun()
 }
+
+// Log in a file of user cache dir, level comes from configuration
+func startLogging(conf config.Config) (io.Closer, error) {
+	path, err := config.LogPath()
+	if err != nil {
+		return nil, err
+	}
+	level, err := conf.Level()
+	if err != nil {
+		slog.Warn("log level ignored", "err", err)
+	}
+	return config.StartLogging(path, level)
+}
@@ -0,0 +1,446 @@
+package mpris
+
+import (
+	"fmt"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const BusName = "org.mpris.MediaPlayer2.music_client"
+const objectPath = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+
+const rootInterface = "org.mpris.MediaPlayer2"
+const playerInterface = "org.mpris.MediaPlayer2.Player"
+const trackListInterface = "org.mpris.MediaPlayer2.TrackList"
+
+// Player doesn't give an absolute volume, each step is one call to VolumeUp or VolumeDown
+const volumeStep = 0.1
+
+// Player is what is controlled through MPRIS, music.MusicWrapper implements it
+type Player interface {
+	Play(index int) error
+	Pause() error
+	UnPause() error
+	Next() error
+	Previous() error
+	VolumeUp() error
+	VolumeDown() error
+	GetPlaylist() ([]music.Music, error)
+	Current() (int, error)
+}
+
+// PauseState is the paused state shared with the other controls of application.
+// MPRIS reads it for its status and marks in it the pauses it sends to player
+type PauseState interface {
+	Paused() bool
+	MarkPaused(paused bool)
+}
+
+// Server exposes the player as an MPRIS media player. Player is given by a function as the controlled one can change
+type Server struct {
+	// Never held while calling props, godbus calls Volume callback with props locked
+	locker sync.Mutex
+	// Serializes refreshes so properties are updated in order
+	refreshLocker sync.Mutex
+	conn          *dbus.Conn
+	player        func() Player
+	props         *prop.Properties
+	pauseState    PauseState
+	tracks        []music.Music
+	// Index of current track, -1 when queue is empty
+	current int
+
+	volumeLocker sync.Mutex
+	volume       float64
+	// Steps not sent to player yet, sent by one goroutine at a time
+	pendingSteps  int
+	sendingVolume bool
+}
+
+// Start connect to session bus, export server and refresh it at each interval until stop is closed
+func Start(player func() Player, pauseState PauseState, interval time.Duration, stop chan struct{}) (*Server, error) {
+	conn, err := dbus.SessionBusPrivate()
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.Auth(nil); err == nil {
+		err = conn.Hello()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	server, err := Export(conn, player, pauseState)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go func() {
+		timer := time.NewTicker(interval)
+		defer timer.Stop()
+		defer conn.Close()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-timer.C:
+				server.Refresh()
+			}
+		}
+	}()
+	return server, nil
+}
+
+// Export register MPRIS interfaces on connection and request the MPRIS bus name
+func Export(conn *dbus.Conn, player func() Player, pauseState PauseState) (*Server, error) {
+	s := &Server{conn: conn, player: player, pauseState: pauseState, current: -1, volume: 0.5}
+	if err := conn.Export(root{s}, objectPath, rootInterface); err != nil {
+		return nil, err
+	}
+	if err := conn.ExportWithMap(playerMethods{s}, playerMapping, objectPath, playerInterface); err != nil {
+		return nil, err
+	}
+	if err := conn.Export(trackListMethods{s}, objectPath, trackListInterface); err != nil {
+		return nil, err
+	}
+	props, err := prop.Export(conn, objectPath, s.properties())
+	if err != nil {
+		return nil, err
+	}
+	s.props = props
+	node := &introspect.Node{
+		Name: string(objectPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{Name: rootInterface, Methods: introspect.Methods(root{s}), Properties: props.Introspection(rootInterface)},
+			{Name: playerInterface, Methods: playerIntrospection(playerMethods{s}), Properties: props.Introspection(playerInterface)},
+			{Name: trackListInterface, Methods: introspect.Methods(trackListMethods{s}), Properties: props.Introspection(trackListInterface)},
+		},
+	}
+	if err := conn.Export(introspect.NewIntrospectable(node), objectPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return nil, err
+	}
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, fmt.Errorf("name %s already taken", BusName)
+	}
+	s.Refresh()
+	return s, nil
+}
+
+func (s *Server) properties() map[string]map[string]*prop.Prop {
+	readOnly := func(value interface{}) *prop.Prop {
+		return &prop.Prop{Value: value, Emit: prop.EmitTrue}
+	}
+	constant := func(value interface{}) *prop.Prop {
+		return &prop.Prop{Value: value, Emit: prop.EmitFalse}
+	}
+	return map[string]map[string]*prop.Prop{
+		rootInterface: {
+			"CanQuit":             constant(false),
+			"CanRaise":            constant(false),
+			"HasTrackList":        constant(true),
+			"Identity":            constant("Music client"),
+			"SupportedUriSchemes": constant([]string{}),
+			"SupportedMimeTypes":  constant([]string{}),
+		},
+		playerInterface: {
+			"PlaybackStatus": readOnly("Stopped"),
+			"Rate":           constant(1.0),
+			"MinimumRate":    constant(1.0),
+			"MaximumRate":    constant(1.0),
+			"Metadata":       readOnly(map[string]dbus.Variant{}),
+			"Volume":         {Value: s.volume, Writable: true, Emit: prop.EmitTrue, Callback: s.changeVolume},
+			"Position":       {Value: int64(0), Emit: prop.EmitFalse},
+			"CanGoNext":      readOnly(false),
+			"CanGoPrevious":  readOnly(false),
+			"CanPlay":        readOnly(false),
+			"CanPause":       constant(true),
+			"CanSeek":        constant(false),
+			"CanControl":     constant(true),
+		},
+		trackListInterface: {
+			"Tracks":        readOnly([]dbus.ObjectPath{}),
+			"CanEditTracks": constant(false),
+		},
+	}
+}
+
+var invalidPathChars = regexp.MustCompile("[^A-Za-z0-9_]")
+
+// Identifier of track at a position of the queue
+func trackId(index int, m music.Music) dbus.ObjectPath {
+	return dbus.ObjectPath(fmt.Sprintf("/org/jotitan/music/track/%d_%s", index, invalidPathChars.ReplaceAllString(m.Id, "_")))
+}
+
+func metadata(index int, m music.Music) map[string]dbus.Variant {
+	return map[string]dbus.Variant{
+		"mpris:trackid": dbus.MakeVariant(trackId(index, m)),
+		"xesam:title":   dbus.MakeVariant(m.Title),
+		"xesam:artist":  dbus.MakeVariant([]string{m.Artist}),
+		"xesam:album":   dbus.MakeVariant(m.Album),
+	}
+}
+
+func (s *Server) status() string {
+	switch {
+	case s.current < 0:
+		return "Stopped"
+	case s.pauseState.Paused():
+		return "Paused"
+	}
+	return "Playing"
+}
+
+// Refresh read queue and current track from player and update properties
+func (s *Server) Refresh() {
+	tracks, err := s.player().GetPlaylist()
+	if err != nil {
+		return
+	}
+	current, err := s.player().Current()
+	if err != nil || current >= len(tracks) {
+		current = -1
+	}
+	s.refreshLocker.Lock()
+	defer s.refreshLocker.Unlock()
+	s.locker.Lock()
+	changed := len(tracks) != len(s.tracks)
+	for i := 0; !changed && i < len(tracks); i++ {
+		changed = tracks[i].Id != s.tracks[i].Id
+	}
+	s.tracks, s.current = tracks, current
+	values := s.snapshot(changed)
+	s.locker.Unlock()
+	values.apply(s)
+}
+
+// Values of properties, computed with lock and set without it
+type snapshot struct {
+	meta                              map[string]dbus.Variant
+	status                            string
+	canGoNext, canGoPrevious, canPlay bool
+	// Nil when tracks didn't change
+	ids     []dbus.ObjectPath
+	current dbus.ObjectPath
+}
+
+// Must be called with lock
+func (s *Server) snapshot(tracksChanged bool) snapshot {
+	values := snapshot{
+		meta:          map[string]dbus.Variant{},
+		status:        s.status(),
+		canGoNext:     s.current >= 0 && s.current < len(s.tracks)-1,
+		canGoPrevious: s.current > 0,
+		canPlay:       len(s.tracks) > 0,
+	}
+	if s.current >= 0 {
+		values.meta = metadata(s.current, s.tracks[s.current])
+	}
+	if tracksChanged {
+		values.ids = s.trackIds()
+		values.current = dbus.ObjectPath("/org/mpris/MediaPlayer2/TrackList/NoTrack")
+		if s.current >= 0 {
+			values.current = values.ids[s.current]
+		}
+	}
+	return values
+}
+
+func (values snapshot) apply(s *Server) {
+	s.props.SetMust(playerInterface, "Metadata", values.meta)
+	s.props.SetMust(playerInterface, "PlaybackStatus", values.status)
+	s.props.SetMust(playerInterface, "CanGoNext", values.canGoNext)
+	s.props.SetMust(playerInterface, "CanGoPrevious", values.canGoPrevious)
+	s.props.SetMust(playerInterface, "CanPlay", values.canPlay)
+	if values.ids != nil {
+		s.props.SetMust(trackListInterface, "Tracks", values.ids)
+		s.conn.Emit(objectPath, trackListInterface+".TrackListReplaced", values.ids, values.current)
+	}
+}
+
+func (s *Server) trackIds() []dbus.ObjectPath {
+	ids := make([]dbus.ObjectPath, len(s.tracks))
+	for i, m := range s.tracks {
+		ids[i] = trackId(i, m)
+	}
+	return ids
+}
+
+// Call player then refresh properties
+func (s *Server) do(action func() error) *dbus.Error {
+	if err := action(); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	s.Refresh()
+	return nil
+}
+
+// Translate an absolute volume to volume up or down calls. Called while properties are locked,
+// so calls to player are sent in background
+func (s *Server) changeVolume(change *prop.Change) *dbus.Error {
+	value, ok := change.Value.(float64)
+	if !ok {
+		return dbus.MakeFailedError(fmt.Errorf("bad volume %v", change.Value))
+	}
+	s.volumeLocker.Lock()
+	defer s.volumeLocker.Unlock()
+	s.pendingSteps += int(math.Round((value - s.volume) / volumeStep))
+	s.volume = value
+	if !s.sendingVolume && s.pendingSteps != 0 {
+		s.sendingVolume = true
+		go s.sendVolume()
+	}
+	return nil
+}
+
+// Send pending steps until there is none left
+func (s *Server) sendVolume() {
+	for {
+		s.volumeLocker.Lock()
+		steps := s.pendingSteps
+		s.pendingSteps = 0
+		if steps == 0 {
+			s.sendingVolume = false
+		}
+		s.volumeLocker.Unlock()
+		if steps == 0 {
+			return
+		}
+		action := s.player().VolumeUp
+		if steps < 0 {
+			action, steps = s.player().VolumeDown, -steps
+		}
+		for i := 0; i < steps; i++ {
+			if err := action(); err != nil {
+				slog.Warn("volume change failed", "err", err)
+				break
+			}
+		}
+	}
+}
+
+type root struct {
+	s *Server
+}
+
+func (r root) Raise() *dbus.Error {
+	return nil
+}
+
+func (r root) Quit() *dbus.Error {
+	return nil
+}
+
+type playerMethods struct {
+	s *Server
+}
+
+// Go methods with a name different of MPRIS one
+var playerMapping = map[string]string{"SeekBy": "Seek"}
+
+func playerIntrospection(p playerMethods) []introspect.Method {
+	methods := introspect.Methods(p)
+	for i, method := range methods {
+		if name, exist := playerMapping[method.Name]; exist {
+			methods[i].Name = name
+		}
+	}
+	return methods
+}
+
+func (p playerMethods) Next() *dbus.Error {
+	return p.s.do(p.s.player().Next)
+}
+
+func (p playerMethods) Previous() *dbus.Error {
+	return p.s.do(p.s.player().Previous)
+}
+
+func (p playerMethods) Pause() *dbus.Error {
+	p.s.pauseState.MarkPaused(true)
+	return p.s.do(p.s.player().Pause)
+}
+
+func (p playerMethods) Play() *dbus.Error {
+	p.s.pauseState.MarkPaused(false)
+	return p.s.do(p.s.player().UnPause)
+}
+
+func (p playerMethods) PlayPause() *dbus.Error {
+	if p.s.pauseState.Paused() {
+		return p.Play()
+	}
+	return p.Pause()
+}
+
+func (p playerMethods) Stop() *dbus.Error {
+	return p.Pause()
+}
+
+func (p playerMethods) SeekBy(offset int64) *dbus.Error {
+	return nil
+}
+
+func (p playerMethods) SetPosition(track dbus.ObjectPath, position int64) *dbus.Error {
+	return nil
+}
+
+func (p playerMethods) OpenUri(uri string) *dbus.Error {
+	return dbus.MakeFailedError(fmt.Errorf("opening %s is not supported", uri))
+}
+
+type trackListMethods struct {
+	s *Server
+}
+
+func (t trackListMethods) GetTracksMetadata(ids []dbus.ObjectPath) ([]map[string]dbus.Variant, *dbus.Error) {
+	t.s.locker.Lock()
+	defer t.s.locker.Unlock()
+	positions := make(map[dbus.ObjectPath]int, len(t.s.tracks))
+	for i, id := range t.s.trackIds() {
+		positions[id] = i
+	}
+	results := make([]map[string]dbus.Variant, 0, len(ids))
+	for _, id := range ids {
+		if i, exist := positions[id]; exist {
+			results = append(results, metadata(i, t.s.tracks[i]))
+		}
+	}
+	return results, nil
+}
+
+func (t trackListMethods) AddTrack(uri string, after dbus.ObjectPath, setAsCurrent bool) *dbus.Error {
+	return dbus.MakeFailedError(fmt.Errorf("adding %s is not supported", uri))
+}
+
+func (t trackListMethods) RemoveTrack(id dbus.ObjectPath) *dbus.Error {
+	return dbus.MakeFailedError(fmt.Errorf("removing %s is not supported", id))
+}
+
+func (t trackListMethods) GoTo(id dbus.ObjectPath) *dbus.Error {
+	t.s.locker.Lock()
+	index := -1
+	for i, trackId := range t.s.trackIds() {
+		if trackId == id {
+			index = i
+		}
+	}
+	t.s.locker.Unlock()
+	if index == -1 {
+		return dbus.MakeFailedError(fmt.Errorf("unknown track %s", id))
+	}
+	t.s.pauseState.MarkPaused(false)
+	return t.s.do(func() error { return t.s.player().Play(index) })
+}
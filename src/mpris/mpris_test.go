@@ -0,0 +1,214 @@
+package mpris
+
+import (
+	"bufio"
+	"github.com/godbus/dbus/v5"
+	"github.com/jotitan/fyne_poc/src/music"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakePlayer struct {
+	locker  sync.Mutex
+	musics  []music.Music
+	current int
+	calls   []string
+}
+
+func (f *fakePlayer) call(name string) error {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	f.calls = append(f.calls, name)
+	switch name {
+	case "next":
+		f.current++
+	case "previous":
+		f.current--
+	}
+	return nil
+}
+
+func (f *fakePlayer) Play(index int) error {
+	f.locker.Lock()
+	f.current = index
+	f.locker.Unlock()
+	return f.call("play")
+}
+func (f *fakePlayer) Pause() error      { return f.call("pause") }
+func (f *fakePlayer) UnPause() error    { return f.call("unpause") }
+func (f *fakePlayer) Next() error       { return f.call("next") }
+func (f *fakePlayer) Previous() error   { return f.call("previous") }
+func (f *fakePlayer) VolumeUp() error   { return f.call("up") }
+func (f *fakePlayer) VolumeDown() error { return f.call("down") }
+
+func (f *fakePlayer) GetPlaylist() ([]music.Music, error) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	return f.musics, nil
+}
+
+func (f *fakePlayer) Current() (int, error) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	return f.current, nil
+}
+
+func (f *fakePlayer) callsString() string {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	return strings.Join(f.calls, ",")
+}
+
+// Paused state of application, changed by MPRIS and by other controls
+type fakePauseState struct {
+	paused atomic.Bool
+}
+
+func (f *fakePauseState) Paused() bool           { return f.paused.Load() }
+func (f *fakePauseState) MarkPaused(paused bool) { f.paused.Store(paused) }
+
+// Volume calls are sent in background, wait for expected calls
+func (f *fakePlayer) waitCalls(t *testing.T, expected string) {
+	for deadline := time.Now().Add(time.Second); f.callsString() != expected; {
+		if time.Now().After(deadline) {
+			t.Fatal("Bad calls", f.callsString(), "expected", expected)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Start a private session bus and return two connections on it
+func startBus(t *testing.T) (*dbus.Conn, *dbus.Conn) {
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not available")
+	}
+	daemon := exec.Command(path, "--session", "--nofork", "--nopidfile", "--print-address")
+	stdout, err := daemon.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = daemon.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		daemon.Process.Kill()
+		daemon.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	connect := func() *dbus.Conn {
+		conn, err := dbus.Dial(strings.TrimSpace(address))
+		if err == nil {
+			if err = conn.Auth(nil); err == nil {
+				err = conn.Hello()
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	return connect(), connect()
+}
+
+func TestMpris(t *testing.T) {
+	serverConn, clientConn := startBus(t)
+	player := &fakePlayer{musics: []music.Music{
+		{Id: "1", Title: "Title 1", Artist: "Artist 1", Album: "Album 1"},
+		{Id: "2", Title: "Title 2", Artist: "Artist 2", Album: "Album 2"},
+	}}
+	pauseState := &fakePauseState{}
+	server, err := Export(serverConn, func() Player { return player }, pauseState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	object := clientConn.Object(BusName, objectPath)
+	property := func(iface, name string) interface{} {
+		value, err := object.GetProperty(iface + "." + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value.Value()
+	}
+
+	if identity := property(rootInterface, "Identity"); identity != "Music client" {
+		t.Error("Bad identity", identity)
+	}
+	metadata := property(playerInterface, "Metadata").(map[string]dbus.Variant)
+	if title := metadata["xesam:title"].Value(); title != "Title 1" {
+		t.Error("Bad title", title)
+	}
+	if artists := metadata["xesam:artist"].Value().([]string); len(artists) != 1 || artists[0] != "Artist 1" {
+		t.Error("Bad artist", artists)
+	}
+
+	if err := object.Call(playerInterface+".PlayPause", 0).Err; err != nil {
+		t.Fatal(err)
+	}
+	if status := property(playerInterface, "PlaybackStatus"); status != "Paused" {
+		t.Error("Must be paused", status)
+	}
+	if err := object.Call(playerInterface+".Next", 0).Err; err != nil {
+		t.Fatal(err)
+	}
+	metadata = property(playerInterface, "Metadata").(map[string]dbus.Variant)
+	if title := metadata["xesam:title"].Value(); title != "Title 2" {
+		t.Error("Next must change metadata", title)
+	}
+	if canGoNext := property(playerInterface, "CanGoNext"); canGoNext != false {
+		t.Error("Last track can't go next")
+	}
+
+	// Volume is sent as relative steps
+	if err := object.SetProperty(playerInterface+".Volume", dbus.MakeVariant(0.8)); err != nil {
+		t.Fatal(err)
+	}
+	player.waitCalls(t, "pause,next,up,up,up")
+	if err := object.SetProperty(playerInterface+".Volume", dbus.MakeVariant(0.7)); err != nil {
+		t.Fatal(err)
+	}
+	player.waitCalls(t, "pause,next,up,up,up,down")
+
+	tracks := property(trackListInterface, "Tracks").([]dbus.ObjectPath)
+	if len(tracks) != 2 {
+		t.Fatal("Must have 2 tracks", tracks)
+	}
+	var metadatas []map[string]dbus.Variant
+	if err := object.Call(trackListInterface+".GetTracksMetadata", 0, tracks[:1]).Store(&metadatas); err != nil {
+		t.Fatal(err)
+	}
+	if len(metadatas) != 1 || metadatas[0]["xesam:album"].Value() != "Album 1" {
+		t.Error("Bad tracks metadata", metadatas)
+	}
+	if err := object.Call(trackListInterface+".GoTo", 0, tracks[0]).Err; err != nil {
+		t.Fatal(err)
+	}
+	if status := property(playerInterface, "PlaybackStatus"); status != "Playing" {
+		t.Error("Must be playing", status)
+	}
+
+	if calls := player.callsString(); calls != "pause,next,up,up,up,down,play" {
+		t.Error("Bad calls", calls)
+	}
+
+	// Paused from application, MPRIS must resume
+	pauseState.MarkPaused(true)
+	server.Refresh()
+	if status := property(playerInterface, "PlaybackStatus"); status != "Paused" {
+		t.Error("Must be paused by application", status)
+	}
+	if err := object.Call(playerInterface+".PlayPause", 0).Err; err != nil {
+		t.Fatal(err)
+	}
+	if calls := player.callsString(); calls != "pause,next,up,up,up,down,play,unpause" || pauseState.Paused() {
+		t.Error("PlayPause must resume", calls)
+	}
+}
@@ -0,0 +1,29 @@
+package music
+
+import "net/http"
+
+type basicAuthTransport struct {
+	username string
+	password string
+	base     http.RoundTripper
+}
+
+func (t basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.SetBasicAuth(t.username, t.password)
+	return t.base.RoundTrip(clone)
+}
+
+// WithBasicAuth make client send credentials with each request. Nothing is done if username is empty
+func WithBasicAuth(client *http.Client, username, password string) *http.Client {
+	if username == "" {
+		return client
+	}
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	withAuth := *client
+	withAuth.Transport = basicAuthTransport{username, password, base}
+	return &withAuth
+}
@@ -0,0 +1,176 @@
+package music
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DJStrategy choose which tracks are appended when queue runs dry
+type DJStrategy int
+
+const (
+	// More songs of the artist of current track
+	SameArtistStrategy = DJStrategy(iota)
+	// Songs of album of current track after it
+	SameAlbumStrategy
+	// Random songs of artists listened recently
+	RecentArtistsStrategy
+)
+
+// Number of played tracks and artists remembered to avoid repeats
+const (
+	recentTracksSize  = 100
+	recentArtistsSize = 10
+)
+
+// ErrNoCandidate is returned when every track of strategy was played recently
+var ErrNoCandidate = errors.New("no track to add")
+
+// AutoDJ append tracks to the queue when fewer than MinRemaining tracks remain after the current one
+type AutoDJ struct {
+	locker       sync.Mutex
+	Strategy     DJStrategy
+	MinRemaining int
+	// Number of tracks added each time
+	BatchSize     int
+	recentTracks  []string
+	recentArtists []string
+	random        *rand.Rand
+}
+
+func NewAutoDJ(strategy DJStrategy, minRemaining, batchSize int) *AutoDJ {
+	return &AutoDJ{
+		Strategy:     strategy,
+		MinRemaining: minRemaining,
+		BatchSize:    batchSize,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Keep the last values of a list, without duplicate
+func remember(values []string, value string, size int) []string {
+	for i, existing := range values {
+		if existing == value {
+			values = append(values[:i], values[i+1:]...)
+			break
+		}
+	}
+	values = append(values, value)
+	if len(values) > size {
+		values = values[len(values)-size:]
+	}
+	return values
+}
+
+// Played must be called on each track change, to know recent tracks and artists
+func (dj *AutoDJ) Played(m Music) {
+	if m.Id == "" {
+		return
+	}
+	dj.locker.Lock()
+	defer dj.locker.Unlock()
+	dj.recentTracks = remember(dj.recentTracks, m.Id, recentTracksSize)
+	if m.Artist != "" {
+		dj.recentArtists = remember(dj.recentArtists, m.Artist, recentArtistsSize)
+	}
+}
+
+// Fill check remaining tracks and append some if needed. Return added tracks
+func (dj *AutoDJ) Fill(mw MusicWrapper, nowPlaying NowPlaying) ([]Music, error) {
+	dj.Played(nowPlaying.Music)
+	if nowPlaying.Music.Id == "" || nowPlaying.Size-nowPlaying.Index-1 >= dj.MinRemaining {
+		return nil, nil
+	}
+	queue, err := mw.GetPlaylist()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := dj.candidates(mw, nowPlaying.Music)
+	if err != nil {
+		return nil, err
+	}
+	selected := dj.choose(candidates, queue)
+	if len(selected) == 0 {
+		return nil, ErrNoCandidate
+	}
+	if err = mw.AddAll(selected); err != nil {
+		return nil, err
+	}
+	for _, m := range selected {
+		dj.Played(m)
+	}
+	return selected, nil
+}
+
+// Tracks which can be added, in order of preference
+func (dj *AutoDJ) candidates(mw MusicWrapper, current Music) ([]Music, error) {
+	switch dj.Strategy {
+	case SameAlbumStrategy:
+		tracks, err := mw.albumTracklist(current)
+		for i, m := range tracks {
+			if m.Id == current.Id {
+				return tracks[i+1:], nil
+			}
+		}
+		return tracks, err
+	case RecentArtistsStrategy:
+		dj.locker.Lock()
+		artists := append([]string{}, dj.recentArtists...)
+		dj.locker.Unlock()
+		var tracks []Music
+		for _, name := range artists {
+			if artist, exist := mw.ArtistOf(Music{Artist: name}); exist {
+				songs, err := mw.SongsOf(artist, ArtistKind)
+				if err != nil {
+					return nil, err
+				}
+				tracks = append(tracks, songs...)
+			}
+		}
+		dj.shuffle(tracks)
+		return tracks, nil
+	default:
+		artist, exist := mw.ArtistOf(current)
+		if !exist {
+			return nil, nil
+		}
+		tracks, err := mw.SongsOf(artist, ArtistKind)
+		dj.shuffle(tracks)
+		return tracks, err
+	}
+}
+
+func (dj *AutoDJ) shuffle(tracks []Music) {
+	dj.locker.Lock()
+	defer dj.locker.Unlock()
+	dj.random.Shuffle(len(tracks), func(i, j int) {
+		tracks[i], tracks[j] = tracks[j], tracks[i]
+	})
+}
+
+// Keep the first candidates which weren't played recently and aren't in queue
+func (dj *AutoDJ) choose(candidates, queue []Music) []Music {
+	dj.locker.Lock()
+	defer dj.locker.Unlock()
+	excluded := make(map[string]struct{}, len(queue)+len(dj.recentTracks))
+	for _, m := range queue {
+		excluded[m.Id] = struct{}{}
+	}
+	for _, id := range dj.recentTracks {
+		excluded[id] = struct{}{}
+	}
+	var selected []Music
+	for _, m := range candidates {
+		if _, exist := excluded[m.Id]; exist {
+			continue
+		}
+		excluded[m.Id] = struct{}{}
+		selected = append(selected, m)
+		if len(selected) == dj.BatchSize {
+			break
+		}
+	}
+	return selected
+}
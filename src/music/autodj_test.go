@@ -0,0 +1,93 @@
+package music
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+func TestAutoDJ(t *testing.T) {
+	fill := func(dj *AutoDJ, ids ...int) ([]int, error) {
+		fake, player := newFakePlayer(t, ids...)
+		fake.set(ids, len(ids)-1)
+		mw := NewMusicWrapper(newFakeServer(t, 12), player)
+		nowPlaying, err := mw.CurrentTrack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = dj.Fill(mw, nowPlaying)
+		queue, _ := fake.state()
+		added := queue[len(ids):]
+		sort.Ints(added)
+		return added, err
+	}
+
+	dj := NewAutoDJ(SameArtistStrategy, 2, 3)
+	dj.random = rand.New(rand.NewSource(1))
+	if added, err := fill(dj, 1, 4); err != nil || len(added) != 2 || added[0] != 7 || added[1] != 10 {
+		t.Error("songs of artist not in queue must be added", added, err)
+	}
+	// Songs of artist 1 were all added recently
+	if added, err := fill(dj, 1); err == nil || len(added) != 0 {
+		t.Error("recent songs must not be added again", added)
+	}
+
+	dj = NewAutoDJ(SameAlbumStrategy, 2, 3)
+	if added, _ := fill(dj, 9, 4); len(added) != 0 {
+		t.Error("only songs after current one in album must be added", added)
+	}
+	if added, _ := fill(NewAutoDJ(SameAlbumStrategy, 2, 3), 4); len(added) != 1 || added[0] != 9 {
+		t.Error("rest of album must be added", added)
+	}
+
+	dj = NewAutoDJ(RecentArtistsStrategy, 1, 10)
+	dj.Played(fakeMusic(2))
+	if added, _ := fill(dj, 3); len(added) != 6 {
+		t.Error("songs of artist 0 and artist 2 not played must be added", added)
+	}
+	if added, _ := fill(NewAutoDJ(SameArtistStrategy, 0, 3), 1); len(added) != 0 {
+		t.Error("nothing must be added while enough songs remain", added)
+	}
+}
+
+func TestSameAlbumName(t *testing.T) {
+	// Two albums are called "best of", songs are listed by server out of track order
+	albums := map[string][]Music{
+		"1": {{Id: "3", Artist: "b", Track: 3}, {Id: "1", Artist: "b", Track: 1}, {Id: "2", Artist: "b", Track: 2}},
+		"2": {{Id: "6", Artist: "a", Track: 3}, {Id: "4", Artist: "a", Track: 1}, {Id: "5", Artist: "a", Track: 2}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listByOnlyAlbums" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.RawQuery == "" {
+			json.NewEncoder(w).Encode([]musicBy{{Name: "Best of", Url: "1"}, {Name: "best of", Url: "2"}})
+			return
+		}
+		var songs []responseBy
+		for _, m := range albums[r.URL.RawQuery] {
+			song := responseBy{Id: m.Id, Title: "title " + m.Id}
+			song.Infos.Artist, song.Infos.Album, song.Infos.Track = m.Artist, "Best of", m.Track
+			songs = append(songs, song)
+		}
+		json.NewEncoder(w).Encode(songs)
+	}))
+	defer server.Close()
+	msw, _ := NewMusicServerWrapperWithClient(server.URL, server.Client())
+	mw := NewMusicWrapper(msw, MusicPlayerWrapper{})
+
+	dj := NewAutoDJ(SameAlbumStrategy, 2, 3)
+	candidates, err := dj.candidates(mw, Music{Id: "4", Artist: "a", Album: "best of"})
+	if err != nil || len(candidates) != 2 || candidates[0].Id != "5" || candidates[1].Id != "6" {
+		t.Error("rest of album of current song must be in track order", candidates, err)
+	}
+	// Unknown song is found by its artist
+	candidates, _ = dj.candidates(mw, Music{Id: "9", Artist: "A", Album: "Best of"})
+	if len(candidates) != 3 || candidates[0].Id != "4" {
+		t.Error("album of artist must be chosen", candidates)
+	}
+}
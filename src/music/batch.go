@@ -0,0 +1,192 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var errBadIndex = errors.New("index out of queue")
+
+// Sorted indexes without duplicates, checked against size of queue. Indexes start at 1
+func checkIndexes(indexes []int, size int) ([]int, error) {
+	unique := make(map[int]struct{}, len(indexes))
+	sorted := make([]int, 0, len(indexes))
+	for _, index := range indexes {
+		if index < 1 || index > size {
+			return nil, fmt.Errorf("%w: %d", errBadIndex, index)
+		}
+		if _, exist := unique[index]; !exist {
+			unique[index] = struct{}{}
+			sorted = append(sorted, index)
+		}
+	}
+	sort.Ints(sorted)
+	return sorted, nil
+}
+
+// DeleteMany remove tracks of queue. Tracks are removed from the end so indexes don't shift
+func (mw MusicWrapper) DeleteMany(entries []QueueEntry) error {
+	var sorted []int
+	return mw.record(&queueOperation{
+		name: "delete",
+		apply: func(mw MusicWrapper) error {
+			return mw.mutate(entries, func(state QueueState, indexes []int) error {
+				var err error
+				if sorted, err = checkIndexes(indexes, len(state.Ids)); err != nil {
+					return err
+				}
+				for i := len(sorted) - 1; i >= 0; i-- {
+					if err = mw.player.Delete(sorted[i]); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+		// Restored from the first one, so each one goes back to its index. Without move, they stay at the end in order
+		revert: func(mw MusicWrapper, before, after []int) error {
+			size := len(after)
+			var lost error
+			for _, index := range sorted {
+				err := restore(mw, before[index-1], index, size)
+				if errors.Is(err, ErrMoveUnsupported) {
+					lost = ErrPositionLost
+				} else if err != nil {
+					return err
+				}
+				size++
+			}
+			return lost
+		},
+	})
+}
+
+// MoveAfter move tracks right after track after, an empty entry moves them to top. Tracks keep their order
+func (mw MusicWrapper) MoveAfter(entries []QueueEntry, after QueueEntry) error {
+	var order []int
+	return mw.record(&queueOperation{
+		name: "move",
+		apply: func(mw MusicWrapper) error {
+			if !mw.CanMove() {
+				return ErrMoveUnsupported
+			}
+			return mw.mutate(entries, func(state QueueState, indexes []int) error {
+				// Anchor can be one of moved tracks, it's located alone
+				anchor := []int{0}
+				if after != (QueueEntry{}) {
+					var err error
+					if anchor, err = locate(state, []QueueEntry{after}); err != nil {
+						return err
+					}
+				}
+				sorted, err := checkIndexes(indexes, len(state.Ids))
+				if err != nil {
+					return err
+				}
+				order = moveOrder(len(state.Ids), sorted, anchor[0])
+				return reorder(mw, order)
+			})
+		},
+		revert: func(mw MusicWrapper, _, _ []int) error {
+			return reorder(mw, inverse(order))
+		},
+	})
+}
+
+// MoveToTop move tracks at the beginning of queue
+func (mw MusicWrapper) MoveToTop(entries []QueueEntry) error {
+	return mw.MoveAfter(entries, QueueEntry{})
+}
+
+// Order of a queue of size tracks once sorted indexes are moved after index after. Positions in result start at 0
+func moveOrder(size int, sorted []int, after int) []int {
+	moved := make(map[int]bool, len(sorted))
+	for _, index := range sorted {
+		moved[index-1] = true
+	}
+	order := make([]int, 0, size)
+	// Tracks staying before moved ones, then moved ones, then the rest
+	for position := 0; position < after; position++ {
+		if !moved[position] {
+			order = append(order, position)
+		}
+	}
+	for _, index := range sorted {
+		order = append(order, index-1)
+	}
+	for position := after; position < size; position++ {
+		if !moved[position] {
+			order = append(order, position)
+		}
+	}
+	return order
+}
+
+// Order which puts back a queue reordered by order
+func inverse(order []int) []int {
+	result := make([]int, len(order))
+	for position, previous := range order {
+		result[previous] = position
+	}
+	return result
+}
+
+// Values of the longest increasing subsequence, they don't need to move
+func longestIncreasing(values []int) map[int]bool {
+	// tails[k] is position in values of the smallest tail of increasing subsequences of length k+1
+	tails := make([]int, 0, len(values))
+	previous := make([]int, len(values))
+	for i, value := range values {
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= value })
+		previous[i] = -1
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	kept := make(map[int]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+			kept[values[i]] = true
+		}
+	}
+	return kept
+}
+
+// Reorder queue so track at order[i] goes at position i, with the minimal number of moves
+func reorder(mw MusicWrapper, order []int) error {
+	// Current queue as final positions of its tracks
+	queue := inverse(order)
+	kept := longestIncreasing(queue)
+	for final := range order {
+		if kept[final] {
+			continue
+		}
+		from := indexOf(queue, final)
+		queue = append(queue[:from], queue[from+1:]...)
+		// Placed right after the track which precedes it at the end
+		to := 0
+		if final > 0 {
+			to = indexOf(queue, final-1) + 1
+		}
+		queue = append(queue[:to], append([]int{final}, queue[to:]...)...)
+		if err := mw.player.Move(from+1, to+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexOf(values []int, value int) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
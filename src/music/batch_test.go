@@ -0,0 +1,97 @@
+package music
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// Entries of a queue where track at index i has id i
+func entries(indexes ...int) []QueueEntry {
+	result := make([]QueueEntry, len(indexes))
+	for i, index := range indexes {
+		result[i] = QueueEntry{Id: strconv.Itoa(index), Index: index}
+	}
+	return result
+}
+
+func TestDeleteMany(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3, 4, 5, 6)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+	if err := mw.DeleteMany(entries(5, 2, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[1 4 6]" {
+		t.Error("bad queue", queue)
+	}
+	if fake.calls["/playlist/remove"] != 3 {
+		t.Error("one call by track", fake.calls["/playlist/remove"])
+	}
+	mw.Undo()
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[1 2 3 4 5 6]" {
+		t.Error("tracks must be restored at their place", queue)
+	}
+	if err := mw.DeleteMany(entries(7)); err == nil {
+		t.Error("index out of queue must be rejected")
+	}
+}
+
+func TestMoveAfter(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3, 4, 5, 6)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+	mw.MoveToTop(entries(5, 3))
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[3 5 1 2 4 6]" {
+		t.Error("bad queue", queue)
+	}
+	if fake.calls["/playlist/move"] != 2 {
+		t.Error("only moved tracks must be moved", fake.calls["/playlist/move"])
+	}
+	// Play next, after track 1 at position 3
+	mw.MoveAfter([]QueueEntry{{Id: "3", Index: 1}, {Id: "6", Index: 6}}, QueueEntry{Id: "1", Index: 3})
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[5 1 3 6 2 4]" {
+		t.Error("bad queue", queue)
+	}
+	mw.Undo()
+	mw.Undo()
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[1 2 3 4 5 6]" {
+		t.Error("moves must be undone", queue)
+	}
+}
+
+func TestMoveOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		size := 1 + random.Intn(12)
+		ids := make([]int, size)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+		var indexes []int
+		for i := 1; i <= size; i++ {
+			if random.Intn(3) == 0 {
+				indexes = append(indexes, i)
+			}
+		}
+		after := random.Intn(size + 1)
+		fake, player := newFakePlayer(t, ids...)
+		mw := NewMusicWrapper(newFakeServer(t, 20), player)
+		anchor := QueueEntry{}
+		if after > 0 {
+			anchor = entries(after)[0]
+		}
+		if err := mw.MoveAfter(entries(indexes...), anchor); err != nil {
+			t.Fatal(err)
+		}
+		expected := make([]int, 0, size)
+		for _, position := range moveOrder(size, indexes, after) {
+			expected = append(expected, position+1)
+		}
+		if queue, _ := fake.state(); fmt.Sprint(queue) != fmt.Sprint(expected) {
+			t.Fatal("bad queue", indexes, after, queue, expected)
+		}
+		if fake.calls["/playlist/move"] > len(indexes) {
+			t.Error("too many moves", indexes, after, fake.calls["/playlist/move"])
+		}
+	}
+}
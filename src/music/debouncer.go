@@ -0,0 +1,45 @@
+package music
+
+import (
+	"sync"
+	"time"
+)
+
+// Delay and minimal length of search terms typed by user
+const SearchDelay = 300 * time.Millisecond
+const SearchMinLength = 3
+
+// Debouncer launch action only when no new value was given during delay, to avoid a request on each key stroke
+type Debouncer struct {
+	locker    sync.Mutex
+	delay     time.Duration
+	minLength int
+	timer     *time.Timer
+	action    func(string)
+}
+
+// NewDebouncer ignore values shorter than minLength
+func NewDebouncer(delay time.Duration, minLength int, action func(string)) *Debouncer {
+	return &Debouncer{delay: delay, minLength: minLength, action: action}
+}
+
+func (d *Debouncer) Input(value string) {
+	if len(value) < d.minLength {
+		return
+	}
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.delay, func() { d.action(value) })
+}
+
+// Stop cancel the pending action
+func (d *Debouncer) Stop() {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+}
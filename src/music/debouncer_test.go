@@ -0,0 +1,32 @@
+package music
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDebouncer(t *testing.T) {
+	values := make(chan string, 10)
+	debouncer := NewDebouncer(50*time.Millisecond, 3, func(value string) { values <- value })
+	for _, value := range []string{"go", "gol", "gold", "goldm"} {
+		debouncer.Input(value)
+	}
+	select {
+	case value := <-values:
+		if value != "goldm" {
+			t.Error("only last value must be searched, got", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("action never launched")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(values) != 0 {
+		t.Error("action must be launched once")
+	}
+	debouncer.Input("jean")
+	debouncer.Stop()
+	time.Sleep(100 * time.Millisecond)
+	if len(values) != 0 {
+		t.Error("stopped action must not be launched")
+	}
+}
@@ -0,0 +1,257 @@
+package music
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/net/dns/dnsmessage"
+	"log/slog"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DNS-SD service types announced by servers and players
+const ServerService = "_musicserver._tcp.local."
+const PlayerService = "_musicplayer._tcp.local."
+
+// Message sent by broadcast probe, endpoints answer with a json Endpoint
+const broadcastProbe = "MUSIC_DISCOVERY"
+
+const ServerEndpoint = "server"
+const PlayerEndpoint = "player"
+
+// Endpoint is a server or a player found on local network
+type Endpoint struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Discoverer struct {
+	// Address where mDNS queries are sent, a loopback responder can be used in tests
+	MDNSAddr string
+	// Address of UDP broadcast probe, empty to disable it
+	BroadcastAddr string
+	// Time to wait for answers
+	Timeout time.Duration
+}
+
+func NewDiscoverer() Discoverer {
+	return Discoverer{
+		MDNSAddr:      "224.0.0.251:5353",
+		BroadcastAddr: "255.255.255.255:9875",
+		Timeout:       2 * time.Second,
+	}
+}
+
+// Discover query the network and return all endpoints which answered before timeout
+func (d Discoverer) Discover() ([]Endpoint, error) {
+	var locker sync.Mutex
+	found := make(map[string]Endpoint)
+	var errs []error
+	add := func(endpoints []Endpoint, err error) {
+		locker.Lock()
+		defer locker.Unlock()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, endpoint := range endpoints {
+			found[endpoint.URL] = endpoint
+		}
+	}
+	waiter := sync.WaitGroup{}
+	if d.MDNSAddr != "" {
+		waiter.Add(1)
+		go func() {
+			add(d.discoverMDNS())
+			waiter.Done()
+		}()
+	}
+	if d.BroadcastAddr != "" {
+		waiter.Add(1)
+		go func() {
+			add(d.discoverBroadcast())
+			waiter.Done()
+		}()
+	}
+	waiter.Wait()
+	// Fail only if no method worked
+	if len(errs) > 0 && len(errs) == countMethods(d) {
+		return nil, errs[0]
+	}
+	endpoints := make([]Endpoint, 0, len(found))
+	for _, endpoint := range found {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Kind != endpoints[j].Kind {
+			return endpoints[i].Kind > endpoints[j].Kind
+		}
+		return endpoints[i].Name < endpoints[j].Name
+	})
+	return endpoints, nil
+}
+
+func countMethods(d Discoverer) int {
+	count := 0
+	if d.MDNSAddr != "" {
+		count++
+	}
+	if d.BroadcastAddr != "" {
+		count++
+	}
+	return count
+}
+
+// Send a query and call read for each received packet until timeout
+func (d Discoverer) exchange(addr string, query []byte, read func(data []byte, from *net.UDPAddr)) error {
+	target, err := net.ResolveUDPAddr("udp4", addr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err = conn.WriteToUDP(query, target); err != nil {
+		return err
+	}
+	conn.SetReadDeadline(time.Now().Add(d.Timeout))
+	buffer := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			// Deadline reached
+			return nil
+		}
+		read(buffer[:n], from)
+	}
+}
+
+func (d Discoverer) discoverBroadcast() ([]Endpoint, error) {
+	var endpoints []Endpoint
+	err := d.exchange(d.BroadcastAddr, []byte(broadcastProbe), func(data []byte, _ *net.UDPAddr) {
+		var endpoint Endpoint
+		if json.Unmarshal(data, &endpoint) == nil && endpoint.URL != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	})
+	return endpoints, err
+}
+
+func (d Discoverer) discoverMDNS() ([]Endpoint, error) {
+	query, err := buildMDNSQuery(ServerService, PlayerService)
+	if err != nil {
+		return nil, err
+	}
+	records := newMDNSRecords()
+	err = d.exchange(d.MDNSAddr, query, records.read)
+	return records.endpoints(), err
+}
+
+func buildMDNSQuery(services ...string) ([]byte, error) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		name, err := dnsmessage.NewName(service)
+		if err != nil {
+			return nil, err
+		}
+		// Top bit of class asks for an unicast response
+		question := dnsmessage.Question{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET | 1<<15}
+		if err := builder.Question(question); err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
+
+type srvRecord struct {
+	target string
+	port   uint16
+}
+
+// Records collected from all mDNS answers
+type mdnsRecords struct {
+	instances map[string]string
+	services  map[string]srvRecord
+	texts     map[string][]string
+	addresses map[string]net.IP
+	// Address of responder, used when no A record is given
+	sources map[string]net.IP
+}
+
+func newMDNSRecords() *mdnsRecords {
+	return &mdnsRecords{
+		instances: make(map[string]string),
+		services:  make(map[string]srvRecord),
+		texts:     make(map[string][]string),
+		addresses: make(map[string]net.IP),
+		sources:   make(map[string]net.IP),
+	}
+}
+
+func (r *mdnsRecords) read(data []byte, from *net.UDPAddr) {
+	var message dnsmessage.Message
+	if err := message.Unpack(data); err != nil {
+		slog.Debug("bad mDNS answer", "from", from, "err", err)
+		return
+	}
+	resources := append(append(message.Answers, message.Authorities...), message.Additionals...)
+	for _, resource := range resources {
+		name := resource.Header.Name.String()
+		switch body := resource.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if service := strings.ToLower(name); service == ServerService || service == PlayerService {
+				instance := body.PTR.String()
+				r.instances[instance] = service
+				r.sources[instance] = from.IP
+			}
+		case *dnsmessage.SRVResource:
+			r.services[name] = srvRecord{body.Target.String(), body.Port}
+		case *dnsmessage.TXTResource:
+			r.texts[name] = body.TXT
+		case *dnsmessage.AResource:
+			r.addresses[name] = net.IP(body.A[:])
+		}
+	}
+}
+
+func (r *mdnsRecords) endpoints() []Endpoint {
+	var endpoints []Endpoint
+	for instance, service := range r.instances {
+		srv, exist := r.services[instance]
+		if !exist {
+			continue
+		}
+		ip, exist := r.addresses[srv.target]
+		if !exist {
+			ip = r.sources[instance]
+		}
+		scheme, path := "http", ""
+		for _, text := range r.texts[instance] {
+			switch {
+			case strings.HasPrefix(text, "scheme="):
+				scheme = text[7:]
+			case strings.HasPrefix(text, "path="):
+				path = text[5:]
+			}
+		}
+		kind := ServerEndpoint
+		if service == PlayerService {
+			kind = PlayerEndpoint
+		}
+		endpoints = append(endpoints, Endpoint{
+			Kind: kind,
+			Name: strings.TrimSuffix(instance, "."+service),
+			URL:  fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(ip.String(), fmt.Sprintf("%d", srv.port)), path),
+		})
+	}
+	return endpoints
+}
@@ -0,0 +1,102 @@
+package music
+
+import (
+	"encoding/json"
+	"golang.org/x/net/dns/dnsmessage"
+	"net"
+	"testing"
+	"time"
+)
+
+// Start a loopback responder answering each packet with the result of answer
+func startResponder(t *testing.T, answer func(query []byte) []byte) string {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	go func() {
+		buffer := make([]byte, 9000)
+		for {
+			n, from, err := conn.ReadFromUDP(buffer)
+			if err != nil {
+				return
+			}
+			if response := answer(buffer[:n]); response != nil {
+				conn.WriteToUDP(response, from)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func mdnsAnswer(t *testing.T, query []byte) []byte {
+	var message dnsmessage.Message
+	if err := message.Unpack(query); err != nil || len(message.Questions) != 2 {
+		t.Error("bad query", err)
+		return nil
+	}
+	name := func(value string) dnsmessage.Name {
+		return dnsmessage.MustNewName(value)
+	}
+	header := func(value string, kind dnsmessage.Type) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name(value), Type: kind, Class: dnsmessage.ClassINET, TTL: 120}
+	}
+	response := dnsmessage.Message{
+		Header: dnsmessage.Header{Response: true, Authoritative: true},
+		Answers: []dnsmessage.Resource{
+			{Header: header(PlayerService, dnsmessage.TypePTR), Body: &dnsmessage.PTRResource{PTR: name("Salon." + PlayerService)}},
+			{Header: header(ServerService, dnsmessage.TypePTR), Body: &dnsmessage.PTRResource{PTR: name("Nas." + ServerService)}},
+		},
+		Additionals: []dnsmessage.Resource{
+			{Header: header("Salon."+PlayerService, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Target: name("salon.local."), Port: 9001}},
+			{Header: header("salon.local.", dnsmessage.TypeA), Body: &dnsmessage.AResource{A: [4]byte{192, 168, 1, 20}}},
+			{Header: header("Nas."+ServerService, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Target: name("nas.local."), Port: 9000}},
+			{Header: header("Nas."+ServerService, dnsmessage.TypeTXT), Body: &dnsmessage.TXTResource{TXT: []string{"scheme=https", "path=/music"}}},
+		},
+	}
+	data, err := response.Pack()
+	if err != nil {
+		t.Error(err)
+	}
+	return data
+}
+
+func TestDiscoverMDNS(t *testing.T) {
+	addr := startResponder(t, func(query []byte) []byte { return mdnsAnswer(t, query) })
+	discoverer := Discoverer{MDNSAddr: addr, Timeout: 300 * time.Millisecond}
+	endpoints, err := discoverer.Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Endpoint{
+		{ServerEndpoint, "Nas", "https://127.0.0.1:9000/music"},
+		{PlayerEndpoint, "Salon", "http://192.168.1.20:9001"},
+	}
+	if len(endpoints) != len(expected) {
+		t.Fatal("bad endpoints", endpoints)
+	}
+	for i, endpoint := range endpoints {
+		if endpoint != expected[i] {
+			t.Error("expected", expected[i], "got", endpoint)
+		}
+	}
+}
+
+func TestDiscoverBroadcast(t *testing.T) {
+	addr := startResponder(t, func(query []byte) []byte {
+		if string(query) != broadcastProbe {
+			return nil
+		}
+		data, _ := json.Marshal(Endpoint{PlayerEndpoint, "Bureau", "http://10.0.0.2:9001"})
+		return data
+	})
+	discoverer := Discoverer{BroadcastAddr: addr, Timeout: 300 * time.Millisecond}
+	endpoints, err := discoverer.Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 || endpoints[0].Name != "Bureau" {
+		t.Error("bad endpoints", endpoints)
+	}
+}
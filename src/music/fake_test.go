@@ -0,0 +1,187 @@
+package music
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePlayer simulates the playlist of a music player
+type fakePlayer struct {
+	locker  sync.Mutex
+	ids     []int
+	current int
+	volume  int
+	paused  bool
+	// Connections are closed without answer, like an unreachable player
+	down bool
+	// Move is unknown, like players without /playlist/move
+	noMove bool
+	// Number of received requests by path
+	calls map[string]int
+}
+
+func newFakePlayer(t *testing.T, ids ...int) (*fakePlayer, MusicPlayerWrapper) {
+	fake := &fakePlayer{ids: ids, calls: make(map[string]int)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, NewMusicPlayerWrapperWithClient(server.URL, server.Client())
+}
+
+func (f *fakePlayer) set(ids []int, current int) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	f.ids, f.current = ids, current
+}
+
+func (f *fakePlayer) setDown(down bool) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	f.down = down
+}
+
+func (f *fakePlayer) state() ([]int, int) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	return append([]int{}, f.ids...), f.current
+}
+
+func (f *fakePlayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	if f.down {
+		if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			conn.Close()
+		}
+		return
+	}
+	f.calls[r.URL.Path]++
+	index, _ := strconv.Atoi(r.URL.Query().Get("index"))
+	switch r.URL.Path {
+	case "/playlist/state":
+		json.NewEncoder(w).Encode(map[string][]int{"ids": f.ids})
+	case "/playlist/current":
+		json.NewEncoder(w).Encode(map[string]int{"current": f.current})
+	case "/playlist/add":
+		var musics []map[string]string
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &musics)
+		for _, m := range musics {
+			id, _ := strconv.Atoi(m["id"])
+			f.ids = append(f.ids, id)
+		}
+	case "/playlist/remove":
+		// Index of remove starts at 1
+		if index < 1 || index > len(f.ids) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.ids = append(f.ids[:index-1], f.ids[index:]...)
+	case "/playlist/move":
+		if f.noMove {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+		to, _ := strconv.Atoi(r.URL.Query().Get("to"))
+		if from < 1 || from > len(f.ids) || to < 1 || to > len(f.ids) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id := f.ids[from-1]
+		f.ids = append(f.ids[:from-1], f.ids[from:]...)
+		f.ids = append(f.ids[:to-1], append([]int{id}, f.ids[to-1:]...)...)
+	case "/music/play":
+		if r.URL.Query().Has("index") {
+			f.current = index
+		}
+		f.paused = false
+	case "/music/pause":
+		f.paused = true
+	case "/music/next":
+		f.current++
+	case "/music/previous":
+		f.current--
+	case "/control/volumeUp":
+		f.volume++
+	case "/control/volumeDown":
+		f.volume--
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Return a server knowing musics with id from 1 to size, music i has artist "artist i%3", album "album i%5", year 2000+i and track i/5+1
+func newFakeServer(t *testing.T, size int) MusicServerWrapper {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/musicsInfo":
+			ids := strings.Split(strings.Trim(r.URL.Query().Get("ids"), "[]"), ",")
+			musics := make([]Music, 0, len(ids))
+			for _, id := range ids {
+				if value, err := strconv.Atoi(id); err == nil && value >= 1 && value <= size {
+					musics = append(musics, fakeMusic(value))
+				}
+			}
+			json.NewEncoder(w).Encode(musics)
+		case "/pathOfMusic":
+			fmt.Fprintf(w, "/music/%s.mp3", r.URL.Query().Get("id"))
+		case "/listByArtist":
+			listBy(w, r, "artist", 3, size)
+		case "/listByOnlyAlbums":
+			listBy(w, r, "album", 5, size)
+		case "/covers/1.jpg":
+			w.Write([]byte("cover 1"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	msw, err := NewMusicServerWrapperWithClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msw
+}
+
+// Without parameter, list artists or albums as index, otherwise list songs of one of them (artist=1 for "artist 1")
+func listBy(w http.ResponseWriter, r *http.Request, kind string, modulo, size int) {
+	value := r.URL.Query().Get(kind)
+	if value == "" {
+		entities := make([]musicBy, 0, modulo)
+		for i := 0; i < modulo && i < size; i++ {
+			entities = append(entities, musicBy{Name: fmt.Sprintf("%s %d", kind, i), Url: fmt.Sprintf("%s=%d", kind, i)})
+		}
+		json.NewEncoder(w).Encode(entities)
+		return
+	}
+	selected, _ := strconv.Atoi(value)
+	musics := make([]responseBy, 0)
+	for id := 1; id <= size; id++ {
+		if id%modulo == selected {
+			m := fakeMusic(id)
+			song := responseBy{Title: m.Title, Id: m.Id}
+			song.Infos.Artist, song.Infos.Album, song.Infos.Year, song.Infos.Genre, song.Infos.Track = m.Artist, m.Album, m.Year, m.Genre, m.Track
+			musics = append(musics, song)
+		}
+	}
+	json.NewEncoder(w).Encode(musics)
+}
+
+func fakeMusic(id int) Music {
+	return Music{
+		Id:     fmt.Sprintf("%d", id),
+		Title:  fmt.Sprintf("title %d", id),
+		Artist: fmt.Sprintf("artist %d", id%3),
+		Album:  fmt.Sprintf("album %d", id%5),
+		Year:   2000 + id,
+		Genre:  []string{"rock", "jazz"}[id%2],
+		Track:  id/5 + 1,
+	}
+}
@@ -0,0 +1,23 @@
+package music
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Write value as indented json in a temporary file then rename it, to never leave a partial file
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	temp := path + ".tmp"
+	if err = os.WriteFile(temp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
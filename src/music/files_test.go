@@ -0,0 +1,29 @@
+package music
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "values.json")
+	if err := writeJSON(path, map[string]int{"a": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSON(path, map[string]int{"b": 2}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values map[string]int
+	if err = json.Unmarshal(data, &values); err != nil || len(values) != 1 || values["b"] != 2 {
+		t.Error("file must be replaced", string(data), err)
+	}
+	if _, err = os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary file must be renamed", err)
+	}
+}
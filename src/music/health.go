@@ -0,0 +1,186 @@
+package music
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthState is the connectivity of server and player
+type HealthState int
+
+const (
+	Connected = HealthState(iota)
+	// Slow answers or a few failures, or server down while player answers
+	Degraded
+	// Player doesn't answer anymore
+	Offline
+)
+
+func (s HealthState) String() string {
+	switch s {
+	case Connected:
+		return "connected"
+	case Degraded:
+		return "degraded"
+	default:
+		return "offline"
+	}
+}
+
+const (
+	// Average latency above which connection is degraded
+	slowLatency = time.Second
+	// Consecutive failures after which an endpoint is down
+	downFailures = 3
+	// Weight of the last probe in average latency
+	latencyWeight = 0.3
+)
+
+// EndpointHealth is the result of last probes of server or player
+type EndpointHealth struct {
+	// Average latency of successful probes
+	Latency   time.Duration
+	Failures  int
+	LastError error
+}
+
+// Down is true after several consecutive failures
+func (e EndpointHealth) Down() bool {
+	return e.Failures >= downFailures
+}
+
+func (e EndpointHealth) healthy() bool {
+	return e.Failures == 0 && e.Latency <= slowLatency
+}
+
+func (e *EndpointHealth) record(latency time.Duration, err error) {
+	if err != nil {
+		e.Failures++
+		e.LastError = err
+		return
+	}
+	e.Failures, e.LastError = 0, nil
+	if e.Latency == 0 {
+		e.Latency = latency
+	} else {
+		e.Latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.Latency))
+	}
+}
+
+// Health is the state of connection with both endpoints
+type Health struct {
+	State  HealthState
+	Server EndpointHealth
+	Player EndpointHealth
+}
+
+func stateOf(server, player EndpointHealth) HealthState {
+	switch {
+	case player.Down():
+		return Offline
+	case !server.healthy() || !player.healthy():
+		return Degraded
+	default:
+		return Connected
+	}
+}
+
+// ServerRecovered is true when server answers again after being down
+func (h Health) ServerRecovered(previous Health) bool {
+	return previous.Server.Down() && !h.Server.Down()
+}
+
+// PlayerRecovered is true when player answers again after being down
+func (h Health) PlayerRecovered(previous Health) bool {
+	return previous.Player.Down() && !h.Player.Down()
+}
+
+// Description is a short text of state, with the reason when it's not connected
+func (h Health) Description() string {
+	switch {
+	case h.State == Connected:
+		return fmt.Sprintf("%s (%d ms)", h.State, h.Player.Latency.Milliseconds())
+	case h.Player.LastError != nil:
+		return fmt.Sprintf("%s: player %v", h.State, h.Player.LastError)
+	case h.Server.LastError != nil:
+		return fmt.Sprintf("%s: server %v", h.State, h.Server.LastError)
+	default:
+		return fmt.Sprintf("%s: slow answers", h.State)
+	}
+}
+
+// HealthMonitor probe server and player, listeners are called when state changes or an endpoint goes down or up
+type HealthMonitor struct {
+	locker    sync.Mutex
+	server    func() error
+	player    func() error
+	health    Health
+	listeners []func(previous, current Health)
+	now       func() time.Time
+}
+
+// NewHealthMonitor use probes of server and player, like PingServer and PingPlayer
+func NewHealthMonitor(server, player func() error) *HealthMonitor {
+	return &HealthMonitor{server: server, player: player, now: time.Now}
+}
+
+// PingServer check server with a light request, ProbeServer loads all artists
+func (mw MusicWrapper) PingServer() error {
+	return probe(fmt.Sprintf("%s/musicsInfo?ids=[]", mw.server.url), mw.server.client)
+}
+
+func (mw MusicWrapper) PingPlayer() error {
+	return ProbePlayer(mw.player.url, mw.player.client)
+}
+
+func (h *HealthMonitor) OnChange(listener func(previous, current Health)) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	h.listeners = append(h.listeners, listener)
+}
+
+func (h *HealthMonitor) Health() Health {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	return h.health
+}
+
+func (h *HealthMonitor) measure(probe func() error) (time.Duration, error) {
+	start := h.now()
+	err := probe()
+	return h.now().Sub(start), err
+}
+
+// Check probe both endpoints and return new health
+func (h *HealthMonitor) Check() Health {
+	serverLatency, serverErr := h.measure(h.server)
+	playerLatency, playerErr := h.measure(h.player)
+	h.locker.Lock()
+	previous := h.health
+	h.health.Server.record(serverLatency, serverErr)
+	h.health.Player.record(playerLatency, playerErr)
+	h.health.State = stateOf(h.health.Server, h.health.Player)
+	current, listeners := h.health, h.listeners
+	h.locker.Unlock()
+	if previous.State != current.State || previous.Server.Down() != current.Server.Down() || previous.Player.Down() != current.Player.Down() {
+		for _, listener := range listeners {
+			listener(previous, current)
+		}
+	}
+	return current
+}
+
+// Watch check endpoints at each interval until stop is closed
+func (h *HealthMonitor) Watch(interval time.Duration, stop <-chan struct{}) {
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+	for {
+		h.Check()
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package music
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthMonitor(t *testing.T) {
+	var serverErr, playerErr error
+	clock := time.Now()
+	latency := 10 * time.Millisecond
+	monitor := NewHealthMonitor(func() error { return serverErr }, func() error {
+		clock = clock.Add(latency)
+		return playerErr
+	})
+	monitor.now = func() time.Time { return clock }
+	var changes []Health
+	monitor.OnChange(func(_, current Health) {
+		changes = append(changes, current)
+	})
+
+	if health := monitor.Check(); health.State != Connected || health.Player.Latency != latency {
+		t.Error("endpoints answer quickly", health)
+	}
+	playerErr = errors.New("unreachable")
+	if health := monitor.Check(); health.State != Degraded || health.Player.Failures != 1 {
+		t.Error("one failure degrades connection", health)
+	}
+	monitor.Check()
+	previous := monitor.Check()
+	if previous.State != Offline {
+		t.Error("player is down after consecutive failures", previous)
+	}
+	playerErr = nil
+	health := monitor.Check()
+	if health.State != Connected || !health.PlayerRecovered(previous) {
+		t.Error("player must recover", health)
+	}
+	// Degraded, offline then connected
+	if len(changes) != 3 {
+		t.Error("listeners are called on changes only", len(changes))
+	}
+
+	latency = 3 * time.Second
+	monitor.Check()
+	monitor.Check()
+	if health := monitor.Health(); health.State != Degraded || health.Player.Latency <= slowLatency {
+		t.Error("slow player degrades connection", health)
+	}
+	latency = 0
+	serverErr = errors.New("server down")
+	for i := 0; i < 10; i++ {
+		monitor.Check()
+	}
+	if health := monitor.Health(); health.State != Degraded || !health.Server.Down() {
+		t.Error("server down only degrades connection", health)
+	}
+}
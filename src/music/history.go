@@ -0,0 +1,137 @@
+package music
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryEntry is a track observed as played by a player
+type HistoryEntry struct {
+	Music    Music         `json:"music"`
+	Player   string        `json:"player"`
+	Start    time.Time     `json:"start"`
+	Listened time.Duration `json:"listened"`
+}
+
+// History is an append only file of entries, one json by line
+type History struct {
+	locker sync.Mutex
+	path   string
+}
+
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+func (h *History) Append(entry HistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Read return entries started between from (included) and to (excluded), sorted by start. Zero times aren't bounds
+func (h *History) Read(from, to time.Time) ([]HistoryEntry, error) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+	file, err := os.Open(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		// A line cut by a crash is skipped
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		if (!from.IsZero() && entry.Start.Before(from)) || (!to.IsZero() && !entry.Start.Before(to)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Start.Before(entries[j].Start)
+	})
+	return entries, scanner.Err()
+}
+
+// HistoryRecorder write in history tracks seen by a TrackWatcher, with time they were listened
+type HistoryRecorder struct {
+	locker  sync.Mutex
+	history *History
+	// Name of the watched player
+	player  func() string
+	playing *HistoryEntry
+	now     func() time.Time
+}
+
+func NewHistoryRecorder(history *History, player func() string) *HistoryRecorder {
+	return &HistoryRecorder{history: history, player: player, now: time.Now}
+}
+
+// Changed is a listener of TrackWatcher, it saves previous track and starts the new one
+func (r *HistoryRecorder) Changed(_, current NowPlaying) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	r.save()
+	if current.Music.Id != "" {
+		r.playing = &HistoryEntry{Music: current.Music, Player: r.player(), Start: r.now()}
+	}
+}
+
+// Close save the track being played
+func (r *HistoryRecorder) Close() error {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	return r.save()
+}
+
+// Listened time is the time between two changes, pauses included, limited to length of music
+func (r *HistoryRecorder) save() error {
+	if r.playing == nil {
+		return nil
+	}
+	entry := *r.playing
+	r.playing = nil
+	entry.Listened = r.now().Sub(entry.Start)
+	if length := entry.Music.Duration(); length > 0 && entry.Listened > length {
+		entry.Listened = length
+	}
+	return r.history.Append(entry)
+}
+
+// AddAll add musics to the queue in the same order
+func (mw MusicWrapper) AddAll(musics []Music) error {
+	list := make([]*Music, len(musics))
+	for i := range musics {
+		m := musics[i]
+		list[i] = &m
+	}
+	return mw.addMany(list)
+}
@@ -0,0 +1,63 @@
+package music
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "history.jsonl")
+	history := NewHistory(path)
+	clock := time.Date(2024, 3, 10, 20, 0, 0, 0, time.Local)
+	recorder := NewHistoryRecorder(history, func() string { return "salon" })
+	recorder.now = func() time.Time { return clock }
+
+	first := fakeMusic(1)
+	first.Length = 60
+	recorder.Changed(NowPlaying{}, NowPlaying{Music: first})
+	clock = clock.Add(5 * time.Minute)
+	recorder.Changed(NowPlaying{Music: first}, NowPlaying{Index: 1, Music: fakeMusic(2)})
+	clock = clock.Add(24 * time.Hour)
+	recorder.Changed(NowPlaying{Index: 1, Music: fakeMusic(2)}, NowPlaying{Index: 2, Music: fakeMusic(3)})
+	clock = clock.Add(2 * time.Minute)
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// A truncated line must not break reading
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	file.WriteString(`{"music":{"id":`)
+	file.Close()
+
+	entries, err := history.Read(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatal("3 tracks must be recorded", entries)
+	}
+	if entries[0].Listened != time.Minute || entries[0].Player != "salon" || entries[0].Music.Title != "title 1" {
+		t.Error("listened time must be limited to length", entries[0])
+	}
+	if entries[2].Listened != 2*time.Minute {
+		t.Error("closing must save current track", entries[2])
+	}
+
+	day := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)
+	entries, _ = history.Read(day, day.AddDate(0, 0, 1))
+	if len(entries) != 1 || entries[0].Music.Id != "3" {
+		t.Error("only entries of the day must be read", entries)
+	}
+}
+
+func TestAddAll(t *testing.T) {
+	fake, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 3), player)
+	if err := mw.AddAll([]Music{fakeMusic(3), fakeMusic(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if ids, _ := fake.state(); len(ids) != 2 || ids[0] != 3 || ids[1] != 1 {
+		t.Error("musics must be added in order", ids)
+	}
+}
@@ -0,0 +1,89 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrConflict is returned when a track to change can't be found safely in queue, someone else changed it
+var ErrConflict = errors.New("queue changed on player")
+
+// QueueEntry is a track of queue, known by its id and the position it had when queue was read. Index starts at 1.
+// Revision is the one of the queue read, 0 when unknown
+type QueueEntry struct {
+	Id       string
+	Index    int
+	Revision uint64
+}
+
+// Queue is the playlist shown to user with the revision of the state it was read from
+type Queue struct {
+	Musics   []Music
+	Revision uint64
+}
+
+// Entry return entry of music displayed at row, rows start at 0
+func (q Queue) Entry(row int) QueueEntry {
+	return QueueEntry{Id: q.Musics[row].Id, Index: row + 1, Revision: q.Revision}
+}
+
+// EntryAt read queue to return entry at index, for clients which only know positions
+func (mw MusicWrapper) EntryAt(index int) (QueueEntry, error) {
+	state, err := mw.player.GetQueue()
+	if err != nil {
+		return QueueEntry{}, err
+	}
+	if index < 1 || index > len(state.Ids) {
+		return QueueEntry{}, fmt.Errorf("%w: %d", errBadIndex, index)
+	}
+	return QueueEntry{Id: strconv.Itoa(state.Ids[index-1]), Index: index, Revision: state.Revision()}, nil
+}
+
+// Find index of entries in queue. A track which moved is found by its id if it is only once in queue.
+// Position of an entry read from another revision isn't trusted, another track with the same id can be there now
+func locate(state QueueState, entries []QueueEntry) ([]int, error) {
+	ids, revision := state.Ids, state.Revision()
+	indexes := make([]int, len(entries))
+	claimed := make(map[int]bool, len(entries))
+	for i, entry := range entries {
+		id, err := strconv.Atoi(entry.Id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad id %q", ErrConflict, entry.Id)
+		}
+		sameRevision := entry.Revision == 0 || entry.Revision == revision
+		if sameRevision && entry.Index >= 1 && entry.Index <= len(ids) && ids[entry.Index-1] == id && !claimed[entry.Index] {
+			indexes[i] = entry.Index
+			claimed[entry.Index] = true
+			continue
+		}
+		found := 0
+		for position, value := range ids {
+			if value == id && !claimed[position+1] {
+				if found != 0 {
+					return nil, fmt.Errorf("%w: track %s is several times in queue", ErrConflict, entry.Id)
+				}
+				found = position + 1
+			}
+		}
+		if found == 0 {
+			return nil, fmt.Errorf("%w: track %s isn't in queue anymore", ErrConflict, entry.Id)
+		}
+		indexes[i] = found
+		claimed[found] = true
+	}
+	return indexes, nil
+}
+
+// Locate entries in current queue then run mutation. Entries are compared with the revision they were read from
+func (mw MusicWrapper) mutate(entries []QueueEntry, run func(state QueueState, indexes []int) error) error {
+	state, err := mw.player.GetQueue()
+	if err != nil {
+		return err
+	}
+	indexes, err := locate(state, entries)
+	if err != nil {
+		return err
+	}
+	return run(state, indexes)
+}
@@ -0,0 +1,73 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	ids := QueueState{Ids: []int{4, 2, 7, 2}}
+	if indexes, err := locate(ids, []QueueEntry{{Id: "7", Index: 3}, {Id: "2", Index: 4}}); err != nil || fmt.Sprint(indexes) != "[3 4]" {
+		t.Error("entries at their position must be found", indexes, err)
+	}
+	if indexes, err := locate(ids, []QueueEntry{{Id: "7", Index: 1}}); err != nil || indexes[0] != 3 {
+		t.Error("moved track must be found by id", indexes, err)
+	}
+	if _, err := locate(ids, []QueueEntry{{Id: "2", Index: 3}}); !errors.Is(err, ErrConflict) {
+		t.Error("moved track which is several times in queue is ambiguous", err)
+	}
+	if _, err := locate(ids, []QueueEntry{{Id: "5", Index: 1}}); !errors.Is(err, ErrConflict) {
+		t.Error("missing track must be a conflict", err)
+	}
+}
+
+func TestDeleteChangedQueue(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+	// Queue read by user, then another client removes the first track
+	entry := QueueEntry{Id: "3", Index: 3}
+	fake.set([]int{2, 3}, 0)
+	if err := mw.Delete(entry); err != nil {
+		t.Fatal(err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[2]" {
+		t.Error("track must be found by its id", queue)
+	}
+	if err := mw.Delete(entry); !errors.Is(err, ErrConflict) {
+		t.Error("removed track must be a conflict", err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[2]" {
+		t.Error("nothing must be removed on conflict", queue)
+	}
+}
+
+func TestDeleteStaleEntry(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 1)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+	queue, err := mw.ReadQueue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another client removes the first track then adds it again, last row is another track 1 now
+	fake.set([]int{2, 1, 1}, 0)
+	if err := mw.Delete(queue.Entry(2)); !errors.Is(err, ErrConflict) {
+		t.Error("entry of an older revision must not be trusted at its position", err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[2 1 1]" {
+		t.Error("nothing must be removed on conflict", queue)
+	}
+	queue, _ = mw.ReadQueue()
+	if err := mw.Delete(queue.Entry(2)); err != nil {
+		t.Error("entry of current revision must be removed", err)
+	}
+}
+
+func TestRevision(t *testing.T) {
+	if (QueueState{Ids: []int{1}, Version: 3}).Revision() != (QueueState{Ids: []int{2}, Version: 3}).Revision() {
+		t.Error("versions of player must be used")
+	}
+	if (QueueState{Ids: []int{1}}).Revision() == (QueueState{Ids: []int{1, 2}}).Revision() {
+		t.Error("ids must be compared without versions")
+	}
+}
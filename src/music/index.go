@@ -0,0 +1,103 @@
+package music
+
+import (
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Group of index for names which don't start with a letter
+const OtherLetter = "#"
+
+// Articles ignored at the beginning of names when sorting, "Les Rita Mitsouko" is under R
+var articles = []string{"les ", "le ", "la ", "l'", "l’", "the "}
+
+// Index lists every artist or album of server in alphabetical order
+type Index struct {
+	Kind    Kind
+	Entries []Music
+	// Position of first entry of each letter
+	letters map[string]int
+}
+
+// SortName return name without its leading article
+func SortName(name string) string {
+	trimmed := strings.TrimSpace(name)
+	lower := strings.ToLower(trimmed)
+	for _, article := range articles {
+		if strings.HasPrefix(lower, article) && len(trimmed) > len(article) {
+			return strings.TrimSpace(trimmed[len(article):])
+		}
+	}
+	return trimmed
+}
+
+// Letter return the letter of name in index, without accent, OtherLetter when it doesn't start with a letter
+func Letter(name string) string {
+	for _, r := range norm.NFD.String(SortName(name)) {
+		r = unicode.ToUpper(r)
+		if r >= 'A' && r <= 'Z' {
+			return string(r)
+		}
+		break
+	}
+	return OtherLetter
+}
+
+// ArtistIndex return all artists, entries are like artists returned by HybridSearch
+func (mw MusicWrapper) ArtistIndex() Index {
+	return newIndex(ArtistKind, mw.server.index().artistDico)
+}
+
+// AlbumIndex return all albums, entries are like albums returned by HybridSearch
+func (mw MusicWrapper) AlbumIndex() Index {
+	return newIndex(AlbumKind, mw.server.index().albumDico)
+}
+
+// Names are sorted with french collation, ignoring articles. Other letters come first, like in a dictionary
+func newIndex(kind Kind, dico map[string]string) Index {
+	entries := make([]Music, 0, len(dico))
+	for id, name := range dico {
+		entries = append(entries, Music{Artist: name, Album: name, Id: id})
+	}
+	collator := collate.New(language.French)
+	keys := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		keys[entry.Id] = SortName(entry.Artist)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if letterA, letterB := Letter(a.Artist), Letter(b.Artist); (letterA == OtherLetter) != (letterB == OtherLetter) {
+			return letterA == OtherLetter
+		}
+		if order := collator.CompareString(keys[a.Id], keys[b.Id]); order != 0 {
+			return order < 0
+		}
+		return a.Id < b.Id
+	})
+	letters := make(map[string]int)
+	for position, entry := range entries {
+		if _, exist := letters[Letter(entry.Artist)]; !exist {
+			letters[Letter(entry.Artist)] = position
+		}
+	}
+	return Index{Kind: kind, Entries: entries, letters: letters}
+}
+
+// Position return position of first entry of letter
+func (i Index) Position(letter string) (int, bool) {
+	position, exist := i.letters[letter]
+	return position, exist
+}
+
+// Letters return OtherLetter then A to Z
+func Letters() []string {
+	letters := []string{OtherLetter}
+	for r := 'A'; r <= 'Z'; r++ {
+		letters = append(letters, string(r))
+	}
+	return letters
+}
@@ -0,0 +1,93 @@
+package music
+
+import (
+	"testing"
+)
+
+func TestSortName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Les Rita Mitsouko": "Rita Mitsouko",
+		"L'Impératrice":     "Impératrice",
+		"The Cure":          "Cure",
+		"La":                "La",
+		"Lescop":            "Lescop",
+	} {
+		if sorted := SortName(name); sorted != expected {
+			t.Error("bad sort name", name, sorted)
+		}
+	}
+	for name, expected := range map[string]string{"Les Rita Mitsouko": "R", "Édith Piaf": "E", "étienne daho": "E", "2Be3": OtherLetter} {
+		if letter := Letter(name); letter != expected {
+			t.Error("bad letter", name, letter)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := newIndex(ArtistKind, map[string]string{
+		"1": "Zazie",
+		"2": "Les Rita Mitsouko",
+		"3": "Étienne Daho",
+		"4": "Eddy Mitchell",
+		"5": "2Be3",
+		"6": "Renaud",
+		"7": "élodie frégé",
+	})
+	expected := []string{"2Be3", "Eddy Mitchell", "élodie frégé", "Étienne Daho", "Renaud", "Les Rita Mitsouko", "Zazie"}
+	for i, entry := range index.Entries {
+		if entry.Artist != expected[i] {
+			t.Fatal("bad order", i, entry.Artist)
+		}
+	}
+	if position, exist := index.Position("R"); !exist || position != 4 {
+		t.Error("bad position of R", position, exist)
+	}
+	if position, exist := index.Position(OtherLetter); !exist || position != 0 {
+		t.Error("bad position of other letters", position, exist)
+	}
+	if _, exist := index.Position("A"); exist {
+		t.Error("no artist starts with A")
+	}
+}
+
+func TestAlbumIndex(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	index := mw.AlbumIndex()
+	if index.Kind != AlbumKind || len(index.Entries) != 5 || index.Entries[0].Album != "album 0" {
+		t.Error("bad album index", index.Entries)
+	}
+	if songs, err := mw.SongsOf(index.Entries[1], index.Kind); err != nil || len(songs) != 3 {
+		t.Error("entries must be usable like search results", songs, err)
+	}
+	if len(Letters()) != 27 {
+		t.Error("bad letters", Letters())
+	}
+}
+
+// Run with -race : index is reloaded by health checks while searches read it
+func TestReloadIndexWhileReading(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := mw.ReloadIndex(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		mw.server.SearchArtists("art")
+		mw.ArtistOf(fakeMusic(1))
+		mw.AlbumIndex()
+		NewRadio(mw, RadioFilter{}, 1, 0)
+	}
+	<-done
+	if index := mw.ArtistIndex(); len(index.Entries) != 3 {
+		t.Error("index must stay loaded", index.Entries)
+	}
+}
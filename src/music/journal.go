@@ -0,0 +1,234 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Number of operations which can be undone
+const journalSize = 50
+
+var (
+	// ErrDiverged is returned when queue changed on player since the operation, undoing it could remove another track
+	ErrDiverged      = errors.New("queue changed on player since the operation")
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+	// ErrPositionLost is returned when deleted tracks are restored at the end of queue, player can't move them back
+	ErrPositionLost = errors.New("tracks restored at the end of queue")
+)
+
+// queueOperation is an edit of the queue with its inverse. Queue ids before and after are kept to detect changes made by someone else
+type queueOperation struct {
+	name  string
+	apply func(mw MusicWrapper) error
+	// Called with queue ids before and after apply
+	revert        func(mw MusicWrapper, before, after []int) error
+	before, after []int
+}
+
+// Journal keep the last queue edits of a player to undo and redo them
+type Journal struct {
+	locker sync.Mutex
+	undo   []*queueOperation
+	redo   []*queueOperation
+}
+
+func equalIds(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Run operation and journal it. When queue can't be read, journal is cleared as operations can't be checked anymore
+func (mw MusicWrapper) record(op *queueOperation) error {
+	if mw.journal == nil {
+		return op.apply(mw)
+	}
+	j := mw.journal
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	before, errBefore := mw.player.GetState()
+	if err := op.apply(mw); err != nil {
+		return err
+	}
+	after, errAfter := mw.player.GetState()
+	if errBefore != nil || errAfter != nil {
+		j.undo, j.redo = nil, nil
+		return nil
+	}
+	op.before, op.after = before, after
+	j.undo = append(j.undo, op)
+	if len(j.undo) > journalSize {
+		j.undo = j.undo[len(j.undo)-journalSize:]
+	}
+	j.redo = nil
+	return nil
+}
+
+// Undo revert the last queue edit. Return name of operation, ErrDiverged if queue changed since then,
+// ErrPositionLost when deleted tracks are back but not at their position
+func (mw MusicWrapper) Undo() (string, error) {
+	if mw.journal == nil {
+		return "", ErrNothingToUndo
+	}
+	j := mw.journal
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	if len(j.undo) == 0 {
+		return "", ErrNothingToUndo
+	}
+	op := j.undo[len(j.undo)-1]
+	state, err := mw.player.GetState()
+	if err != nil {
+		return op.name, err
+	}
+	if !equalIds(state, op.after) {
+		// Older operations depend on this one, none can be undone safely
+		j.undo, j.redo = nil, nil
+		return op.name, ErrDiverged
+	}
+	j.undo = j.undo[:len(j.undo)-1]
+	if err = op.revert(mw, op.before, op.after); err != nil {
+		j.redo = nil
+		return op.name, err
+	}
+	j.redo = append(j.redo, op)
+	return op.name, nil
+}
+
+// Redo apply again the last undone edit. Return name of operation, ErrDiverged if queue changed since undo
+func (mw MusicWrapper) Redo() (string, error) {
+	if mw.journal == nil {
+		return "", ErrNothingToRedo
+	}
+	j := mw.journal
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	if len(j.redo) == 0 {
+		return "", ErrNothingToRedo
+	}
+	op := j.redo[len(j.redo)-1]
+	state, err := mw.player.GetState()
+	if err != nil {
+		return op.name, err
+	}
+	if !equalIds(state, op.before) {
+		j.redo = nil
+		return op.name, ErrDiverged
+	}
+	j.redo = j.redo[:len(j.redo)-1]
+	if err = op.apply(mw); err != nil {
+		return op.name, err
+	}
+	if op.after, err = mw.player.GetState(); err != nil {
+		j.undo = nil
+		return op.name, err
+	}
+	j.undo = append(j.undo, op)
+	return op.name, nil
+}
+
+// CanUndo and CanRedo tell if an operation is journaled, it can still fail if queue diverged
+func (mw MusicWrapper) CanUndo() bool {
+	return mw.journal != nil && mw.journal.size(true) > 0
+}
+
+func (mw MusicWrapper) CanRedo() bool {
+	return mw.journal != nil && mw.journal.size(false) > 0
+}
+
+func (j *Journal) size(undo bool) int {
+	j.locker.Lock()
+	defer j.locker.Unlock()
+	if undo {
+		return len(j.undo)
+	}
+	return len(j.redo)
+}
+
+// Remove the count last tracks of a queue of size tracks
+func removeLast(mw MusicWrapper, size, count int) error {
+	for index := size; index > size-count && index > 0; index-- {
+		if err := mw.player.Delete(index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addOperation(name string, count int, apply func(mw MusicWrapper) error) *queueOperation {
+	return &queueOperation{
+		name:  name,
+		apply: apply,
+		revert: func(mw MusicWrapper, _, after []int) error {
+			return removeLast(mw, len(after), count)
+		},
+	}
+}
+
+// Append a track and move it to its previous position, index starts at 1. Without move, ErrMoveUnsupported is returned once it's appended
+func restore(mw MusicWrapper, id, index, size int) error {
+	if err := mw.add(Music{Id: fmt.Sprintf("%d", id)}); err != nil {
+		return err
+	}
+	if index > size {
+		return nil
+	}
+	return mw.player.Move(size+1, index)
+}
+
+// Move a track in queue to position to, starting at 1
+func (mw MusicWrapper) Move(entry QueueEntry, to int) error {
+	var from int
+	return mw.record(&queueOperation{
+		name: "move",
+		apply: func(mw MusicWrapper) error {
+			if !mw.CanMove() {
+				return ErrMoveUnsupported
+			}
+			return mw.mutate([]QueueEntry{entry}, func(state QueueState, indexes []int) error {
+				if to < 1 || to > len(state.Ids) {
+					return fmt.Errorf("%w: %d", errBadIndex, to)
+				}
+				from = indexes[0]
+				return mw.player.Move(from, to)
+			})
+		},
+		revert: func(mw MusicWrapper, _, _ []int) error {
+			return mw.player.Move(to, from)
+		},
+	})
+}
+
+// CanMove is false once player answered it can't move tracks
+func (mw MusicWrapper) CanMove() bool {
+	return mw.player.CanMove()
+}
+
+// Clear remove all tracks of queue
+func (mw MusicWrapper) Clear() error {
+	return mw.record(&queueOperation{
+		name: "clear",
+		apply: func(mw MusicWrapper) error {
+			ids, err := mw.player.GetState()
+			if err != nil {
+				return err
+			}
+			return removeLast(mw, len(ids), len(ids))
+		},
+		revert: func(mw MusicWrapper, before, _ []int) error {
+			musics := make([]*Music, len(before))
+			for i, id := range before {
+				musics[i] = &Music{Id: fmt.Sprintf("%d", id)}
+			}
+			return mw.addManyWithPaths(musics)
+		},
+	})
+}
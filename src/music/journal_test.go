@@ -0,0 +1,77 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+	expect := func(step string, ids ...int) {
+		t.Helper()
+		if queue, _ := fake.state(); fmt.Sprint(queue) != fmt.Sprint(ids) {
+			t.Errorf("%s: queue is %v, expected %v", step, queue, ids)
+		}
+	}
+
+	mw.Delete(QueueEntry{Id: "2", Index: 2})
+	mw.Add(fakeMusic(4))
+	mw.AddAll([]Music{fakeMusic(5), fakeMusic(6)})
+	mw.Move(QueueEntry{Id: "1", Index: 1}, 3)
+	expect("edits", 3, 4, 1, 5, 6)
+	for _, step := range []struct {
+		name string
+		ids  []int
+	}{{"move", []int{1, 3, 4, 5, 6}}, {"add", []int{1, 3, 4}}, {"add", []int{1, 3}}, {"delete", []int{1, 2, 3}}} {
+		if name, err := mw.Undo(); err != nil || name != step.name {
+			t.Fatal("undo failed", name, err)
+		}
+		expect("undo "+step.name, step.ids...)
+	}
+	if _, err := mw.Undo(); err != ErrNothingToUndo {
+		t.Error("journal must be empty", err)
+	}
+	mw.Redo()
+	mw.Redo()
+	expect("redo", 1, 3, 4)
+
+	mw.Clear()
+	expect("clear")
+	mw.Undo()
+	expect("undo clear", 1, 3, 4)
+
+	// Someone else changed queue, last track isn't the one added anymore
+	mw.Add(fakeMusic(7))
+	fake.set([]int{1, 3, 4, 7, 8}, 0)
+	if _, err := mw.Undo(); err != ErrDiverged {
+		t.Error("diverged queue must be detected", err)
+	}
+	expect("diverged", 1, 3, 4, 7, 8)
+	if mw.CanUndo() || mw.CanRedo() {
+		t.Error("journal must be cleared after divergence")
+	}
+}
+
+func TestWithoutMove(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3, 4)
+	fake.noMove = true
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+
+	if err := mw.MoveToTop([]QueueEntry{{Id: "3", Index: 3}}); !errors.Is(err, ErrMoveUnsupported) || mw.CanMove() {
+		t.Fatal("move must be detected as unsupported", err)
+	}
+	if err := mw.DeleteMany([]QueueEntry{{Id: "1", Index: 1}, {Id: "3", Index: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mw.Undo(); !errors.Is(err, ErrPositionLost) {
+		t.Error("lost position must be told", err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[2 4 1 3]" {
+		t.Error("deleted tracks must be appended in order", queue)
+	}
+	if calls := fake.calls["/playlist/move"]; calls != 1 {
+		t.Error("unsupported move must be asked once", calls)
+	}
+}
@@ -0,0 +1,110 @@
+package music
+
+import (
+	"sort"
+	"strings"
+)
+
+// Album of an artist, grouped from its songs
+type Album struct {
+	Name   string
+	Artist string
+	// Oldest year of songs, 0 when unknown
+	Year  int
+	Songs []Music
+}
+
+// AlbumsOf return albums of an artist returned by HybridSearch, oldest first. Songs of each album are in tracklist order
+func (mw MusicWrapper) AlbumsOf(artist Music) ([]Album, error) {
+	songs, err := mw.SongsOf(artist, ArtistKind)
+	if err != nil {
+		return nil, err
+	}
+	return groupAlbums(songs), nil
+}
+
+// Tracklist return songs of an album returned by HybridSearch, in order
+func (mw MusicWrapper) Tracklist(album Music) ([]Music, error) {
+	songs, err := mw.SongsOf(album, AlbumKind)
+	sortTracks(songs)
+	return songs, err
+}
+
+// Tracklist of the album of a song. Albums sharing its name are told apart by the song, then by its artist
+func (mw MusicWrapper) albumTracklist(m Music) ([]Music, error) {
+	var sameArtist []Music
+	for _, album := range findAllIn(mw.server.index().albumDico, m.Album) {
+		tracks, err := mw.Tracklist(album)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range tracks {
+			if track.Id == m.Id {
+				return tracks, nil
+			}
+			if sameArtist == nil && strings.EqualFold(track.Artist, m.Artist) {
+				sameArtist = tracks
+			}
+		}
+	}
+	return sameArtist, nil
+}
+
+func groupAlbums(songs []Music) []Album {
+	positions := make(map[string]int)
+	var albums []Album
+	for _, song := range songs {
+		position, exist := positions[song.Album]
+		if !exist {
+			position = len(albums)
+			positions[song.Album] = position
+			albums = append(albums, Album{Name: song.Album, Artist: song.Artist})
+		}
+		album := &albums[position]
+		album.Songs = append(album.Songs, song)
+		if song.Year != 0 && (album.Year == 0 || song.Year < album.Year) {
+			album.Year = song.Year
+		}
+	}
+	for _, album := range albums {
+		sortTracks(album.Songs)
+	}
+	// Unknown years at the end
+	sort.SliceStable(albums, func(i, j int) bool {
+		a, b := albums[i], albums[j]
+		if (a.Year == 0) != (b.Year == 0) {
+			return b.Year == 0
+		}
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		return a.Name < b.Name
+	})
+	return albums
+}
+
+// Sort by track number, songs without one keep server order after the others
+func sortTracks(songs []Music) {
+	sort.SliceStable(songs, func(i, j int) bool {
+		a, b := songs[i].Track, songs[j].Track
+		if (a == 0) != (b == 0) {
+			return b == 0
+		}
+		return a < b
+	})
+}
+
+// AddAndPlay append songs to the queue and play the first one
+func (mw MusicWrapper) AddAndPlay(songs []Music) error {
+	if len(songs) == 0 {
+		return nil
+	}
+	ids, err := mw.player.GetState()
+	if err != nil {
+		return err
+	}
+	if err = mw.AddAll(songs); err != nil {
+		return err
+	}
+	return mw.Play(len(ids))
+}
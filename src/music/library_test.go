@@ -0,0 +1,94 @@
+package music
+
+import (
+	"testing"
+)
+
+func TestAlbumsOf(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	// Artist 1 has songs 1, 4, 7, 10 and 13, each one in a different album
+	albums, err := mw.AlbumsOf(Music{Id: "artist=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"album 1", "album 4", "album 2", "album 0", "album 3"}
+	if len(albums) != len(expected) {
+		t.Fatal("bad albums", albums)
+	}
+	for i, album := range albums {
+		if album.Name != expected[i] || album.Artist != "artist 1" || len(album.Songs) != 1 || album.Year != album.Songs[0].Year {
+			t.Error("bad album", i, album)
+		}
+	}
+}
+
+func TestGroupAlbums(t *testing.T) {
+	albums := groupAlbums([]Music{
+		{Id: "1", Album: "b", Track: 2, Year: 1999},
+		{Id: "2", Album: "unknown"},
+		{Id: "3", Album: "b", Track: 1, Year: 1998},
+		{Id: "4", Album: "a", Year: 2010},
+	})
+	if len(albums) != 3 || albums[0].Name != "b" || albums[1].Name != "a" || albums[2].Name != "unknown" {
+		t.Fatal("albums must be sorted by year, unknown ones last", albums)
+	}
+	if albums[0].Year != 1998 || albums[0].Songs[0].Id != "3" || albums[0].Songs[1].Id != "1" {
+		t.Error("bad first album", albums[0])
+	}
+}
+
+func TestTracklist(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	songs, err := mw.Tracklist(Music{Id: "album=0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(songs) != 3 || songs[0].Id != "5" || songs[2].Id != "15" || songs[0].Track != 2 {
+		t.Error("bad tracklist", songs)
+	}
+
+	unordered := []Music{{Id: "a"}, {Id: "b", Track: 3}, {Id: "c"}, {Id: "d", Track: 1}}
+	sortTracks(unordered)
+	for i, id := range []string{"d", "b", "a", "c"} {
+		if unordered[i].Id != id {
+			t.Fatal("songs without track must stay at the end in order", unordered)
+		}
+	}
+}
+
+func TestAddAndPlay(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	if err := mw.AddAndPlay([]Music{fakeMusic(7), fakeMusic(8)}); err != nil {
+		t.Fatal(err)
+	}
+	ids, current := fake.state()
+	if len(ids) != 4 || ids[2] != 7 || current != 2 {
+		t.Error("first added song must be played", ids, current)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	fake, player := newFakePlayer(t, 1)
+	mw := NewMusicWrapper(newFakeServer(t, 15), player)
+
+	// Fake server has no search
+	if _, _, err := mw.HybridSearch("title 1"); err == nil {
+		t.Error("search error must be returned")
+	}
+	mw.server.url += "/missing"
+	if _, err := mw.AlbumsOf(Music{Id: "artist=1"}); err == nil {
+		t.Error("albums error must be returned")
+	}
+	if err := mw.AddAllAlbum(Music{Id: "album=0"}); err == nil {
+		t.Error("add of album must fail when its songs can't be listed")
+	}
+	if ids, _ := fake.state(); len(ids) != 1 {
+		t.Error("nothing must be added", ids)
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"log/slog"
 	"math"
 	"net/http"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type Kind string
@@ -25,6 +28,13 @@ type Music struct {
 	Title  string `json:"title"`
 	Id     string `json:"id"`
 	Path   string `json:"path"`
+	// Duration in seconds and url of cover, only when server gives them
+	Length int    `json:"length,omitempty"`
+	Cover  string `json:"cover,omitempty"`
+	// Year of release, genre and position in album, only when server gives them
+	Year  int    `json:"year,omitempty"`
+	Genre string `json:"genre,omitempty"`
+	Track int    `json:"track,omitempty"`
 }
 
 type musicBy struct {
@@ -32,36 +42,64 @@ type musicBy struct {
 	Url  string `json:"url"`
 }
 
-type MusicServerWrapper struct {
-	url          string
+// Artists and albums of server, replaced as a whole when index is reloaded
+type catalog struct {
 	artistTokens tokens
 	albumTokens  tokens
 	artistDico   map[string]string
 	albumDico    map[string]string
 }
 
+type MusicServerWrapper struct {
+	url    string
+	client *http.Client
+	// Shared by copies of wrapper, swapped while searches read it
+	catalog *atomic.Pointer[catalog]
+}
+
 func NewMusicServerWrapper(url string) MusicServerWrapper {
-	msw := &MusicServerWrapper{url: url}
-	msw.loadArtists()
-	msw.loadAlbums()
+	msw, _ := NewMusicServerWrapperWithClient(url, http.DefaultClient)
+	return msw
+}
 
-	return *msw
+// NewMusicServerWrapperWithClient use a specific client (for TLS configuration) and return loading errors
+func NewMusicServerWrapperWithClient(url string, client *http.Client) (MusicServerWrapper, error) {
+	msw := NewLightMusicServerWrapper(url, client)
+	loaded, err := msw.loadCatalog()
+	msw.catalog.Store(loaded)
+	return msw, err
 }
 
-func (nsw *MusicServerWrapper) loadArtists() error {
-	var err error
-	err, nsw.artistTokens, nsw.artistDico = nsw.loadSome("listByArtist")
-	return err
+// NewLightMusicServerWrapper doesn't load artists and albums, searching by artist or album won't return anything
+func NewLightMusicServerWrapper(url string, client *http.Client) MusicServerWrapper {
+	return MusicServerWrapper{url: url, client: client, catalog: &atomic.Pointer[catalog]{}}
 }
 
-func (nsw *MusicServerWrapper) loadAlbums() error {
-	var err error
-	err, nsw.albumTokens, nsw.albumDico = nsw.loadSome("listByOnlyAlbums")
-	return err
+// Load artists then albums, return what was loaded with the first error
+func (nsw MusicServerWrapper) loadCatalog() (*catalog, error) {
+	loaded := &catalog{}
+	err, artistTokens, artistDico := nsw.loadSome("listByArtist")
+	loaded.artistTokens, loaded.artistDico = artistTokens, artistDico
+	errAlbums, albumTokens, albumDico := nsw.loadSome("listByOnlyAlbums")
+	loaded.albumTokens, loaded.albumDico = albumTokens, albumDico
+	if err == nil {
+		err = errAlbums
+	}
+	return loaded, err
+}
+
+// Current artists and albums, empty when not loaded
+func (nsw MusicServerWrapper) index() catalog {
+	if nsw.catalog != nil {
+		if loaded := nsw.catalog.Load(); loaded != nil {
+			return *loaded
+		}
+	}
+	return catalog{}
 }
 
 func (nsw *MusicServerWrapper) loadSome(url string) (error, tokens, map[string]string) {
-	resp, err := http.Get(fmt.Sprintf("%s/%s", nsw.url, url))
+	resp, err := nsw.client.Get(fmt.Sprintf("%s/%s", nsw.url, url))
 	if err != nil {
 		return err, nil, nil
 	}
@@ -151,7 +189,7 @@ func createTokensTable(values []musicBy) tokens {
 func (nsw *MusicServerWrapper) getIdsFromPositions(positions []int) []string {
 	var results []string
 	for _, pos := range positions {
-		results = append(results, nsw.artistTokens[pos].ids...)
+		results = append(results, nsw.index().artistTokens[pos].ids...)
 	}
 	return results
 }
@@ -162,19 +200,25 @@ type responseBy struct {
 	Infos struct {
 		Album  string `json:"album"`
 		Artist string `json:"artist"`
+		Year   int    `json:"year,omitempty"`
+		Genre  string `json:"genre,omitempty"`
+		Track  int    `json:"track,omitempty"`
 	} `json:"infos"`
 }
 
-func (nsw MusicServerWrapper) GetMusicsByAlbum(idArtist string) []*Music {
+func (nsw MusicServerWrapper) GetMusicsByAlbum(idArtist string) ([]*Music, error) {
 	return nsw.getMusicsBy(fmt.Sprintf("%s/listByOnlyAlbums?%s", nsw.url, idArtist))
 }
 
-func (nsw MusicServerWrapper) GetMusicsByArtist(idArtist string) []*Music {
+func (nsw MusicServerWrapper) GetMusicsByArtist(idArtist string) ([]*Music, error) {
 	return nsw.getMusicsBy(fmt.Sprintf("%s/listByArtist?%s", nsw.url, idArtist))
 }
 
-func (nsw MusicServerWrapper) getMusicsBy(url string) []*Music {
-	tempMusics := doSearch[responseBy](url)
+func (nsw MusicServerWrapper) getMusicsBy(url string) ([]*Music, error) {
+	tempMusics, err := doSearch[responseBy](nsw.client, url)
+	if err != nil {
+		return nil, err
+	}
 	musics := make([]*Music, len(tempMusics))
 	for i, m := range tempMusics {
 		musics[i] = &Music{
@@ -182,17 +226,22 @@ func (nsw MusicServerWrapper) getMusicsBy(url string) []*Music {
 			Title:  m.Title,
 			Artist: m.Infos.Artist,
 			Album:  m.Infos.Album,
+			Year:   m.Infos.Year,
+			Genre:  m.Infos.Genre,
+			Track:  m.Infos.Track,
 		}
 	}
-	return musics
+	return musics, nil
 }
 
 func (nsw MusicServerWrapper) SearchArtists(text string) []Music {
-	return nsw.searchSome(text, nsw.artistTokens, nsw.artistDico)
+	index := nsw.index()
+	return nsw.searchSome(text, index.artistTokens, index.artistDico)
 }
 
 func (nsw MusicServerWrapper) SearchAlbums(text string) []Music {
-	return nsw.searchSome(text, nsw.albumTokens, nsw.albumDico)
+	index := nsw.index()
+	return nsw.searchSome(text, index.albumTokens, index.albumDico)
 }
 
 func (nsw MusicServerWrapper) searchSome(text string, tks tokens, dico map[string]string) []Music {
@@ -219,7 +268,7 @@ func (nsw MusicServerWrapper) searchSome(text string, tks tokens, dico map[strin
 	musics := make([]Music, len(results))
 	for i, id := range results {
 		some := dico[id]
-		musics[i] = Music{some, some, "", id, ""}
+		musics[i] = Music{Artist: some, Album: some, Id: id}
 	}
 	return musics
 }
@@ -256,30 +305,31 @@ func intersect(a, b []string) []string {
 	return results
 }
 
-func (nsw MusicServerWrapper) HybridSearch(term string) ([]Music, Kind) {
-	fmt.Println("Search")
+func (nsw MusicServerWrapper) HybridSearch(term string) ([]Music, Kind, error) {
+	slog.Debug("search", "term", term)
 	if strings.HasPrefix(term, ":") {
 		// specific case
 		if strings.HasPrefix(term, ":artist ") {
-			return nsw.SearchArtists(term[8:]), ArtistKind
+			return nsw.SearchArtists(term[8:]), ArtistKind, nil
 		}
 		if strings.HasPrefix(term, ":album ") {
-			return nsw.SearchAlbums(term[7:]), AlbumKind
+			return nsw.SearchAlbums(term[7:]), AlbumKind, nil
 		}
 
 		if strings.HasPrefix(term, ":album ") {
-			return []Music{}, AlbumKind
+			return []Music{}, AlbumKind, nil
 		}
 	}
-	return nsw.Search(term), SongKind
+	musics, err := nsw.Search(term)
+	return musics, SongKind, err
 }
 
-func (nsw MusicServerWrapper) Search(term string) []Music {
-	return doSearch[Music](fmt.Sprintf("%s/search?term=%s&size=30", nsw.url, strings.ReplaceAll(term, " ", "%20")))
+func (nsw MusicServerWrapper) Search(term string) ([]Music, error) {
+	return doSearch[Music](nsw.client, fmt.Sprintf("%s/search?term=%s&size=30", nsw.url, strings.ReplaceAll(term, " ", "%20")))
 }
 
 func (nsw MusicServerWrapper) doSearch(url string) []Music {
-	resp, err := http.Get(url)
+	resp, err := nsw.client.Get(url)
 	if err != nil {
 		return []Music{}
 	}
@@ -292,18 +342,21 @@ func (nsw MusicServerWrapper) doSearch(url string) []Music {
 	return musics
 }
 
-func doSearch[R Music | responseBy](url string) []R {
-	resp, err := http.Get(url)
+func doSearch[R Music | responseBy](client *http.Client, url string) ([]R, error) {
+	resp, err := client.Get(url)
 	if err != nil {
-		return []R{}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("server answered %s for %s", resp.Status, resp.Request.URL.Path)
 	}
 	data, _ := io.ReadAll(resp.Body)
 	musics := make([]R, 0)
-	err = json.Unmarshal(data, &musics)
-	if err != nil {
-		return []R{}
+	if err = json.Unmarshal(data, &musics); err != nil {
+		return nil, fmt.Errorf("bad response of server for %s : %w", resp.Request.URL.Path, err)
 	}
-	return musics
+	return musics, nil
 }
 
 func (nsw MusicServerWrapper) GetMusics(ids []int) ([]Music, error) {
@@ -312,7 +365,7 @@ func (nsw MusicServerWrapper) GetMusics(ids []int) ([]Music, error) {
 		strIds[i] = fmt.Sprintf("%d", id)
 	}
 
-	resp, err := http.Get(fmt.Sprintf("%s/musicsInfo?ids=[%s]", nsw.url, strings.Join(strIds, ",")))
+	resp, err := nsw.client.Get(fmt.Sprintf("%s/musicsInfo?ids=[%s]", nsw.url, strings.Join(strIds, ",")))
 	if err != nil {
 		return nil, err
 	}
@@ -323,7 +376,7 @@ func (nsw MusicServerWrapper) GetMusics(ids []int) ([]Music, error) {
 }
 
 func (nsw MusicServerWrapper) FindPath(id string) (string, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/pathOfMusic?id=%s", nsw.url, id))
+	resp, err := nsw.client.Get(fmt.Sprintf("%s/pathOfMusic?id=%s", nsw.url, id))
 	if err == nil && resp.StatusCode == 200 {
 		data, err := io.ReadAll(resp.Body)
 		return string(data), err
@@ -331,33 +384,63 @@ func (nsw MusicServerWrapper) FindPath(id string) (string, error) {
 	return "", err
 }
 
+// ErrMoveUnsupported is returned by players without /playlist/move, their tracks can only be added or removed
+var ErrMoveUnsupported = errors.New("player can't move tracks")
+
 type MusicPlayerWrapper struct {
-	url string
+	url    string
+	client *http.Client
+	// Set once player answered it has no move, shared between copies
+	noMove *atomic.Bool
 }
 
 func NewMusicPlayerWrapper(url string) MusicPlayerWrapper {
-	return MusicPlayerWrapper{url: url}
+	return NewMusicPlayerWrapperWithClient(url, http.DefaultClient)
 }
 
-func (mpw MusicPlayerWrapper) GetState() ([]int, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/playlist/state", mpw.url))
+func NewMusicPlayerWrapperWithClient(url string, client *http.Client) MusicPlayerWrapper {
+	return MusicPlayerWrapper{url: url, client: client, noMove: &atomic.Bool{}}
+}
+
+// QueueState is the playlist of a player. Version is only given by players counting changes of their playlist
+type QueueState struct {
+	Ids     []int `json:"ids"`
+	Version int64 `json:"version,omitempty"`
+}
+
+// Revision identifies a state of queue : version of player, or a hash of ids when player doesn't count changes
+func (q QueueState) Revision() uint64 {
+	if q.Version != 0 {
+		return uint64(q.Version)
+	}
+	hash := fnv.New64a()
+	for _, id := range q.Ids {
+		fmt.Fprintf(hash, "%d,", id)
+	}
+	return hash.Sum64()
+}
+
+func (mpw MusicPlayerWrapper) GetQueue() (QueueState, error) {
+	resp, err := mpw.client.Get(fmt.Sprintf("%s/playlist/state", mpw.url))
 	if err == nil && resp.StatusCode == 200 {
 		data, _ := io.ReadAll(resp.Body)
-		value := struct {
-			Ids []int `json:"ids"`
-		}{}
+		value := QueueState{}
 		if err := json.Unmarshal(data, &value); err != nil {
-			return nil, err
+			return QueueState{}, err
 		}
-		return value.Ids, nil
+		return value, nil
 	} else {
-		return nil, err
+		return QueueState{}, checkResponse(resp, err)
 	}
 }
 
+func (mpw MusicPlayerWrapper) GetState() ([]int, error) {
+	state, err := mpw.GetQueue()
+	return state.Ids, err
+}
+
 func (mpw MusicPlayerWrapper) Play(index int) error {
-	_, err := http.Get(fmt.Sprintf("%s/music/play?index=%d", mpw.url, index))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/music/play?index=%d", mpw.url, index)))
 }
 
 func (mpw MusicPlayerWrapper) Add(m Music, path string) error {
@@ -366,8 +449,7 @@ func (mpw MusicPlayerWrapper) Add(m Music, path string) error {
 	}}
 	dataRequest, _ := json.Marshal(request)
 	postUrl := fmt.Sprintf("%s/playlist/add", mpw.url)
-	_, err := http.Post(postUrl, "application/json", bytes.NewBuffer(dataRequest))
-	return err
+	return checkResponse(mpw.client.Post(postUrl, "application/json", bytes.NewBuffer(dataRequest)))
 }
 
 func (mpw MusicPlayerWrapper) AddMany(listMusics []*Music) error {
@@ -379,47 +461,59 @@ func (mpw MusicPlayerWrapper) AddMany(listMusics []*Music) error {
 	}
 	dataRequest, _ := json.Marshal(request)
 	postUrl := fmt.Sprintf("%s/playlist/add", mpw.url)
-	_, err := http.Post(postUrl, "application/json", bytes.NewBuffer(dataRequest))
-	return err
+	return checkResponse(mpw.client.Post(postUrl, "application/json", bytes.NewBuffer(dataRequest)))
 }
 
 func (mpw MusicPlayerWrapper) Delete(index int) error {
-	_, err := http.Get(fmt.Sprintf("%s/playlist/remove?index=%d", mpw.url, index))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/playlist/remove?index=%d", mpw.url, index)))
+}
+
+// Move a track of playlist, indexes start at 1 like Delete.
+// Move isn't in all players, ErrMoveUnsupported is returned once player answered it doesn't know it
+func (mpw MusicPlayerWrapper) Move(from, to int) error {
+	if !mpw.CanMove() {
+		return ErrMoveUnsupported
+	}
+	resp, err := mpw.client.Get(fmt.Sprintf("%s/playlist/move?from=%d&to=%d", mpw.url, from, to))
+	if err == nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		mpw.noMove.Store(true)
+		return ErrMoveUnsupported
+	}
+	return checkResponse(resp, err)
+}
+
+// CanMove is false once player answered it can't move tracks
+func (mpw MusicPlayerWrapper) CanMove() bool {
+	return !mpw.noMove.Load()
 }
 
 func (mpw MusicPlayerWrapper) UnPause() error {
-	_, err := http.Get(fmt.Sprintf("%s/music/play", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/music/play", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) Pause() error {
-	_, err := http.Get(fmt.Sprintf("%s/music/pause", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/music/pause", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) Next() error {
-	_, err := http.Get(fmt.Sprintf("%s/music/next", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/music/next", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) Previous() error {
-	_, err := http.Get(fmt.Sprintf("%s/music/previous", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/music/previous", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) VolumeUp() error {
-	_, err := http.Get(fmt.Sprintf("%s/control/volumeUp", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/control/volumeUp", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) VolumeDown() error {
-	_, err := http.Get(fmt.Sprintf("%s/control/volumeDown", mpw.url))
-	return err
+	return checkResponse(mpw.client.Get(fmt.Sprintf("%s/control/volumeDown", mpw.url)))
 }
 
 func (mpw MusicPlayerWrapper) Current() (int, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/playlist/current", mpw.url))
+	resp, err := mpw.client.Get(fmt.Sprintf("%s/playlist/current", mpw.url))
 	if err == nil && resp.StatusCode == 200 {
 		data, err := io.ReadAll(resp.Body)
 		response := struct {
@@ -431,34 +525,68 @@ func (mpw MusicPlayerWrapper) Current() (int, error) {
 		}
 		return 0, err
 	}
-	return 0, err
+	return 0, checkResponse(resp, err)
+}
+
+// Return an error if request failed or if player doesn't answer with a 200
+func checkResponse(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("player answered %s for %s", resp.Status, resp.Request.URL.Path)
+	}
+	return nil
 }
 
 type MusicWrapper struct {
-	server MusicServerWrapper
+	// Shared between copies to reload index
+	server *MusicServerWrapper
 	player MusicPlayerWrapper
+	// Local favorites and stars, optional
+	ratings *Ratings
+	// Queue edits of player and commands waiting for it, shared between copies
+	journal *Journal
+	outbox  *Outbox
 }
 
 func NewMusicWrapper(server MusicServerWrapper, player MusicPlayerWrapper) MusicWrapper {
-	return MusicWrapper{server, player}
+	return MusicWrapper{server: &server, player: player, journal: &Journal{}, outbox: &Outbox{}}
+}
+
+// ReloadIndex reload artists and albums from server, current ones are kept if loading fails
+func (mw MusicWrapper) ReloadIndex() error {
+	loaded, err := mw.server.loadCatalog()
+	if err != nil {
+		return err
+	}
+	mw.server.catalog.Store(loaded)
+	return nil
 }
 
 func (mw MusicWrapper) GetPlaylist() ([]Music, error) {
-	ids, err := mw.player.GetState()
+	queue, err := mw.ReadQueue()
+	return queue.Musics, err
+}
+
+// ReadQueue return playlist with the revision of player state, entries of its rows can be checked against queue when it's changed
+func (mw MusicWrapper) ReadQueue() (Queue, error) {
+	state, err := mw.player.GetQueue()
 	if err == nil {
 		// Reorder musics according to original state
-		musics, err := mw.server.GetMusics(ids)
+		musics, err := mw.server.GetMusics(state.Ids)
 		if err != nil {
-			return nil, err
+			return Queue{}, err
 		}
 		musicsById := getMusicsAsMap(musics)
-		orderedMusics := make([]Music, len(ids))
-		for index, id := range ids {
+		orderedMusics := make([]Music, len(state.Ids))
+		for index, id := range state.Ids {
 			orderedMusics[index] = musicsById[fmt.Sprintf("%d", id)]
 		}
-		return orderedMusics, nil
+		return Queue{Musics: orderedMusics, Revision: state.Revision()}, nil
 	}
-	return nil, err
+	return Queue{}, err
 }
 
 func getMusicsAsMap(musics []Music) map[string]Music {
@@ -473,11 +601,16 @@ func (mw MusicWrapper) Play(index int) error {
 	return mw.player.Play(index)
 }
 
-func (mw MusicWrapper) Search(term string) []Music {
+func (mw MusicWrapper) Search(term string) ([]Music, error) {
 	return mw.server.Search(term)
 }
 
-func (mw MusicWrapper) HybridSearch(term string) ([]Music, Kind) {
+// HybridSearch search on server, a rating filter like rating>=4 restricts results to rated songs
+func (mw MusicWrapper) HybridSearch(term string) ([]Music, Kind, error) {
+	if rest, min, max, ok := ParseRatingFilter(term); ok {
+		musics, err := mw.searchRated(rest, min, max)
+		return musics, SongKind, err
+	}
 	return mw.server.HybridSearch(term)
 }
 
@@ -486,6 +619,12 @@ func (mw MusicWrapper) SearchArtist(term string) ([]Music, error) {
 }
 
 func (mw MusicWrapper) Add(m Music) error {
+	return mw.record(addOperation("add", 1, func(mw MusicWrapper) error {
+		return mw.add(m)
+	}))
+}
+
+func (mw MusicWrapper) add(m Music) error {
 	// Extract path before
 	path, err := mw.server.FindPath(m.Id)
 	if err == nil {
@@ -495,11 +634,20 @@ func (mw MusicWrapper) Add(m Music) error {
 }
 
 func (mw MusicWrapper) addMany(musics []*Music) error {
+	return mw.record(addOperation("add", len(musics), func(mw MusicWrapper) error {
+		return mw.addManyWithPaths(musics)
+	}))
+}
+
+func (mw MusicWrapper) addManyWithPaths(musics []*Music) error {
 	waiter := sync.WaitGroup{}
 	for _, m := range musics {
 		waiter.Add(1)
 		go func(mus *Music) {
-			path, _ := mw.server.FindPath(mus.Id)
+			path, err := mw.server.FindPath(mus.Id)
+			if err != nil {
+				slog.Warn("no path for music", "id", mus.Id, "err", err)
+			}
 			mus.Path = path
 			waiter.Done()
 		}(m)
@@ -508,8 +656,9 @@ func (mw MusicWrapper) addMany(musics []*Music) error {
 	return mw.player.AddMany(musics)
 }
 
-func (mw MusicWrapper) Delete(index int) error {
-	return mw.player.Delete(index)
+// Delete remove a track of queue, found by its id if it moved. ErrConflict is returned if it can't be found
+func (mw MusicWrapper) Delete(entry QueueEntry) error {
+	return mw.DeleteMany([]QueueEntry{entry})
 }
 
 func (mw MusicWrapper) Pause() error {
@@ -541,17 +690,45 @@ func (mw MusicWrapper) Next() error {
 }
 
 func (mw MusicWrapper) AddAllArtist(m Music) error {
-	return mw.addMany(mw.server.GetMusicsByArtist(m.Id))
+	musics, err := mw.server.GetMusicsByArtist(m.Id)
+	if err != nil {
+		return err
+	}
+	return mw.addMany(musics)
 }
 
 func (mw MusicWrapper) AddAllAlbum(m Music) error {
-	return mw.addMany(mw.server.GetMusicsByAlbum(m.Id))
+	musics, err := mw.server.GetMusicsByAlbum(m.Id)
+	if err != nil {
+		return err
+	}
+	return mw.addMany(musics)
 }
 
-func (mw MusicWrapper) ShowArtist(m Music) []*Music {
+func (mw MusicWrapper) ShowArtist(m Music) ([]*Music, error) {
 	return mw.server.GetMusicsByArtist(m.Id)
 }
 
-func (mw MusicWrapper) ShowAlbum(m Music) []*Music {
+func (mw MusicWrapper) ShowAlbum(m Music) ([]*Music, error) {
 	return mw.server.GetMusicsByAlbum(m.Id)
 }
+
+// SongsOf return songs of an artist or an album returned by HybridSearch
+func (mw MusicWrapper) SongsOf(m Music, kind Kind) ([]Music, error) {
+	var musics []*Music
+	var err error
+	switch kind {
+	case ArtistKind:
+		musics, err = mw.ShowArtist(m)
+	case AlbumKind:
+		musics, err = mw.ShowAlbum(m)
+	}
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Music, len(musics))
+	for i, m := range musics {
+		results[i] = *m
+	}
+	return results, nil
+}
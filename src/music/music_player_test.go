@@ -11,7 +11,7 @@ func TestTest(t *testing.T) {
 	server := NewMusicServerWrapper("url with both")
 	//fmt.Println(NewMusicWrapper(server, player).GetPlaylist())
 
-	artists, _ := server.HybridSearch(":artist jean gold")
+	artists, _, _ := server.HybridSearch(":artist jean gold")
 	fmt.Println(artists)
 	//server.SearchArtists("jean gold")
 }
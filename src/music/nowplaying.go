@@ -0,0 +1,127 @@
+package music
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress estimate position in current track from the time it started, to avoid polling player every second
+type Progress struct {
+	locker  sync.Mutex
+	length  time.Duration
+	started time.Time
+	// Time spent in pause before the current one
+	pausedFor time.Duration
+	pausedAt  time.Time
+	known     bool
+}
+
+// Start a new track of given length (0 if unknown) at now
+func (p *Progress) Start(length time.Duration, now time.Time) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	wasPaused := !p.pausedAt.IsZero()
+	p.length, p.started, p.pausedFor, p.pausedAt, p.known = length, now, 0, time.Time{}, true
+	if wasPaused {
+		p.pausedAt = now
+	}
+}
+
+// Unknown is used for a track already playing when watched for the first time, its position can't be estimated
+func (p *Progress) Unknown(length time.Duration) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	p.length, p.known = length, false
+}
+
+func (p *Progress) Pause(now time.Time) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	if p.pausedAt.IsZero() {
+		p.pausedAt = now
+	}
+}
+
+func (p *Progress) Resume(now time.Time) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	if !p.pausedAt.IsZero() {
+		p.pausedFor += now.Sub(p.pausedAt)
+		p.pausedAt = time.Time{}
+	}
+}
+
+// Position return elapsed time and length of track, ok is false if position is unknown
+func (p *Progress) Position(now time.Time) (elapsed, length time.Duration, ok bool) {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	if !p.known {
+		return 0, p.length, false
+	}
+	if !p.pausedAt.IsZero() {
+		now = p.pausedAt
+	}
+	elapsed = now.Sub(p.started) - p.pausedFor
+	if p.length > 0 && elapsed > p.length {
+		elapsed = p.length
+	}
+	return elapsed, p.length, true
+}
+
+// Duration return length of music, 0 if unknown
+func (m Music) Duration() time.Duration {
+	return time.Duration(m.Length) * time.Second
+}
+
+// Search name in an index of artists or albums, several ones can share a name. They're sorted by id to always choose the same one
+func findAllIn(dico map[string]string, name string) []Music {
+	var found []Music
+	for id, value := range dico {
+		if strings.EqualFold(value, name) {
+			found = append(found, Music{Artist: value, Album: value, Id: id})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Id < found[j].Id })
+	return found
+}
+
+func findIn(dico map[string]string, name string) (Music, bool) {
+	if found := findAllIn(dico, name); len(found) > 0 {
+		return found[0], true
+	}
+	return Music{}, false
+}
+
+// ArtistOf return the artist of a song, as returned by a search on artists
+func (mw MusicWrapper) ArtistOf(m Music) (Music, bool) {
+	return findIn(mw.server.index().artistDico, m.Artist)
+}
+
+// AlbumOf return the album of a song, as returned by a search on albums
+func (mw MusicWrapper) AlbumOf(m Music) (Music, bool) {
+	return findIn(mw.server.index().albumDico, m.Album)
+}
+
+// Cover download cover of music, relative urls are resolved against server
+func (mw MusicWrapper) Cover(m Music) ([]byte, error) {
+	if m.Cover == "" {
+		return nil, fmt.Errorf("no cover for %s", m.Title)
+	}
+	url := m.Cover
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = fmt.Sprintf("%s/%s", mw.server.url, strings.TrimPrefix(url, "/"))
+	}
+	resp, err := mw.server.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("server answered %s for cover of %s", resp.Status, m.Title)
+	}
+	return io.ReadAll(resp.Body)
+}
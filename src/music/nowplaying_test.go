@@ -0,0 +1,42 @@
+package music
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	progress := Progress{}
+	progress.Unknown(3 * time.Minute)
+	if _, _, ok := progress.Position(start); ok {
+		t.Error("position of a track already playing is unknown")
+	}
+	progress.Start(3*time.Minute, start)
+	if elapsed, length, _ := progress.Position(start.Add(time.Minute)); elapsed != time.Minute || length != 3*time.Minute {
+		t.Error("bad position", elapsed, length)
+	}
+	progress.Pause(start.Add(time.Minute))
+	if elapsed, _, _ := progress.Position(start.Add(2 * time.Minute)); elapsed != time.Minute {
+		t.Error("position must not move during pause", elapsed)
+	}
+	progress.Resume(start.Add(2 * time.Minute))
+	if elapsed, _, _ := progress.Position(start.Add(150 * time.Second)); elapsed != 90*time.Second {
+		t.Error("pause must be removed from position", elapsed)
+	}
+	if elapsed, _, _ := progress.Position(start.Add(time.Hour)); elapsed != 3*time.Minute {
+		t.Error("position can't exceed length", elapsed)
+	}
+}
+
+func TestCover(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 3), player)
+	data, err := mw.Cover(Music{Title: "title 1", Cover: "/covers/1.jpg"})
+	if err != nil || string(data) != "cover 1" {
+		t.Error("cover must be read from server", string(data), err)
+	}
+	if _, err := mw.Cover(Music{Title: "title 2", Cover: "covers/2.jpg"}); err == nil {
+		t.Error("missing cover must fail")
+	}
+}
@@ -0,0 +1,209 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// CommandKind is a mutating command which can wait for the player
+type CommandKind int
+
+const (
+	AddCommand = CommandKind(iota)
+	DeleteCommand
+	// Next when steps are positive, previous when negative
+	SkipCommand
+	VolumeCommand
+)
+
+// Command is kept in outbox while player is unreachable
+type Command struct {
+	Kind    CommandKind
+	Musics  []Music
+	Entries []QueueEntry
+	// Number of skips or volume steps, negative to go back or down
+	Steps int
+}
+
+func NewAddCommand(musics ...Music) Command {
+	return Command{Kind: AddCommand, Musics: musics}
+}
+
+func NewDeleteCommand(entries ...QueueEntry) Command {
+	return Command{Kind: DeleteCommand, Entries: entries}
+}
+
+func NewSkipCommand(steps int) Command {
+	return Command{Kind: SkipCommand, Steps: steps}
+}
+
+func NewVolumeCommand(steps int) Command {
+	return Command{Kind: VolumeCommand, Steps: steps}
+}
+
+// Outbox hold commands sent while player was unreachable, to replay them in order
+type Outbox struct {
+	locker   sync.Mutex
+	commands []Command
+}
+
+// ReplayError is returned by Send when waiting commands replayed before the sent one were refused.
+// Err is the error of the sent command itself, nil when it was run or kept
+type ReplayError struct {
+	Refused error
+	Err     error
+}
+
+func (e *ReplayError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("waiting command refused: %v", e.Refused)
+	}
+	return fmt.Sprintf("%v (waiting command refused: %v)", e.Err, e.Refused)
+}
+
+func (e *ReplayError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Refused}
+	}
+	return []error{e.Err, e.Refused}
+}
+
+// Unreachable is true when player can't be joined (dial failure, connection refused, reset or closed, timeout).
+// Player answering an error isn't unreachable, neither are bad urls, unknown hosts or untrusted certificates
+func Unreachable(err error) bool {
+	var untrusted *UntrustedCertificateError
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil, errors.As(err, &untrusted), errors.As(err, &dnsErr) && !dnsErr.Timeout():
+		return false
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Merge consecutive commands of the same kind : five volume up become one +5, skips and volume changes cancelling each other are dropped
+func collapse(commands []Command) []Command {
+	var result []Command
+	for _, cmd := range commands {
+		if last := len(result) - 1; last >= 0 && result[last].Kind == cmd.Kind {
+			previous := &result[last]
+			previous.Steps += cmd.Steps
+			previous.Musics = append(previous.Musics, cmd.Musics...)
+			previous.Entries = append(previous.Entries, cmd.Entries...)
+			continue
+		}
+		cmd.Musics = append([]Music{}, cmd.Musics...)
+		cmd.Entries = append([]QueueEntry{}, cmd.Entries...)
+		result = append(result, cmd)
+	}
+	kept := result[:0]
+	for _, cmd := range result {
+		if (cmd.Kind == SkipCommand || cmd.Kind == VolumeCommand) && cmd.Steps == 0 {
+			continue
+		}
+		kept = append(kept, cmd)
+	}
+	return kept
+}
+
+// Run a command. Steps are done one by one, on error cmd keeps the remaining ones
+func (mw MusicWrapper) execute(cmd *Command) error {
+	switch cmd.Kind {
+	case AddCommand:
+		return mw.AddAll(cmd.Musics)
+	case DeleteCommand:
+		return mw.DeleteMany(cmd.Entries)
+	}
+	forward, backward := mw.Next, mw.Previous
+	if cmd.Kind == VolumeCommand {
+		forward, backward = mw.VolumeUp, mw.VolumeDown
+	}
+	for cmd.Steps != 0 {
+		step, run := 1, forward
+		if cmd.Steps < 0 {
+			step, run = -1, backward
+		}
+		if err := run(); err != nil {
+			return err
+		}
+		cmd.Steps -= step
+	}
+	return nil
+}
+
+// Send run command on player. When player is unreachable or still can't replay waiting commands, it's kept in outbox and nil is returned.
+// Waiting commands refused during replay are returned in a ReplayError, apart from the error of cmd
+func (mw MusicWrapper) Send(cmd Command) error {
+	if mw.outbox == nil {
+		return mw.execute(&cmd)
+	}
+	o := mw.outbox
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	var refused error
+	if len(o.commands) > 0 {
+		// Waiting commands first, to keep order
+		if refused = mw.replay(); Unreachable(refused) {
+			o.commands = collapse(append(o.commands, cmd))
+			return nil
+		}
+	}
+	err := mw.execute(&cmd)
+	if Unreachable(err) {
+		o.commands = append(o.commands, cmd)
+		err = nil
+	}
+	if refused != nil {
+		return &ReplayError{Refused: refused, Err: err}
+	}
+	return err
+}
+
+// Pending return number of commands waiting for player
+func (mw MusicWrapper) Pending() int {
+	if mw.outbox == nil {
+		return 0
+	}
+	mw.outbox.locker.Lock()
+	defer mw.outbox.locker.Unlock()
+	return len(mw.outbox.commands)
+}
+
+// Replay send waiting commands, collapsed. They stay in outbox if player is still unreachable.
+// A command refused by player is dropped, as it would be refused again, first refusal is returned
+func (mw MusicWrapper) Replay() error {
+	if mw.outbox == nil {
+		return nil
+	}
+	mw.outbox.locker.Lock()
+	defer mw.outbox.locker.Unlock()
+	return mw.replay()
+}
+
+func (mw MusicWrapper) replay() error {
+	o := mw.outbox
+	commands := collapse(o.commands)
+	var refused error
+	for i := range commands {
+		err := mw.execute(&commands[i])
+		if Unreachable(err) {
+			o.commands = commands[i:]
+			return err
+		}
+		if err != nil && refused == nil {
+			refused = err
+		}
+	}
+	o.commands = nil
+	return refused
+}
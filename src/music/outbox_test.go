@@ -0,0 +1,129 @@
+package music
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCollapse(t *testing.T) {
+	commands := collapse([]Command{
+		NewVolumeCommand(1), NewVolumeCommand(1), NewVolumeCommand(1),
+		NewSkipCommand(1), NewSkipCommand(-1),
+		NewAddCommand(fakeMusic(1)), NewAddCommand(fakeMusic(2)),
+		NewVolumeCommand(-1),
+	})
+	if len(commands) != 3 {
+		t.Fatal("bad commands", commands)
+	}
+	if commands[0].Kind != VolumeCommand || commands[0].Steps != 3 {
+		t.Error("volume ups must be merged", commands[0])
+	}
+	if commands[1].Kind != AddCommand || len(commands[1].Musics) != 2 {
+		t.Error("adds must be merged, skips cancelling each other dropped", commands[1])
+	}
+	if commands[2].Steps != -1 {
+		t.Error("volume down after adds must stay", commands[2])
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + listener.Addr().String()
+	listener.Close()
+	_, refused := http.Get(closed)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	_, timeout := (&http.Client{Timeout: 10 * time.Millisecond}).Get(slow.URL)
+
+	_, badScheme := http.Get("ftp://localhost/music")
+	reset := &url.Error{Op: "Get", URL: "http://player", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}
+	unknownHost := &url.Error{Op: "Get", URL: "http://player", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "player"}}}
+	untrusted := &url.Error{Op: "Get", URL: "https://player", Err: &UntrustedCertificateError{Host: "player"}}
+
+	for _, test := range []struct {
+		name        string
+		err         error
+		unreachable bool
+	}{
+		{"refused", refused, true},
+		{"timeout", timeout, true},
+		{"reset", reset, true},
+		{"bad scheme", badScheme, false},
+		{"unknown host", unknownHost, false},
+		{"untrusted certificate", untrusted, false},
+		{"player error", ErrConflict, false},
+		{"no error", nil, false},
+	} {
+		if Unreachable(test.err) != test.unreachable {
+			t.Error(test.name, "must be unreachable", test.unreachable, test.err)
+		}
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2, 3)
+	mw := NewMusicWrapper(newFakeServer(t, 10), player)
+
+	fake.setDown(true)
+	for i := 0; i < 5; i++ {
+		if err := mw.Send(NewVolumeCommand(1)); err != nil {
+			t.Fatal("command must be queued", err)
+		}
+	}
+	mw.Send(NewDeleteCommand(QueueEntry{Id: "2", Index: 2}))
+	mw.Send(NewAddCommand(fakeMusic(4)))
+	mw.Send(NewSkipCommand(1))
+	// Waiting commands are collapsed each time another one is sent
+	if mw.Pending() != 4 {
+		t.Error("commands must wait", mw.Pending())
+	}
+	if err := mw.Replay(); !Unreachable(err) || mw.Pending() == 0 {
+		t.Error("commands must wait while player is down", err)
+	}
+
+	fake.setDown(false)
+	if err := mw.Replay(); err != nil {
+		t.Fatal(err)
+	}
+	if mw.Pending() != 0 {
+		t.Error("outbox must be empty", mw.Pending())
+	}
+	queue, current := fake.state()
+	if fmt.Sprint(queue) != "[1 3 4]" || current != 1 || fake.volume != 5 {
+		t.Error("commands must be replayed in order", queue, current, fake.volume)
+	}
+	if fake.calls["/control/volumeUp"] != 5 {
+		t.Error("bad volume calls", fake.calls["/control/volumeUp"])
+	}
+
+	// A refused command isn't kept
+	if err := mw.Send(NewDeleteCommand(QueueEntry{Id: "9", Index: 9})); !errors.Is(err, ErrConflict) || mw.Pending() != 0 {
+		t.Error("refused command must be returned", err)
+	}
+
+	// A waiting command refused when another one is sent doesn't fail the new one
+	fake.setDown(true)
+	mw.Send(NewDeleteCommand(QueueEntry{Id: "9", Index: 9}))
+	fake.setDown(false)
+	err := mw.Send(NewAddCommand(fakeMusic(5)))
+	var replayErr *ReplayError
+	if !errors.As(err, &replayErr) || !errors.Is(replayErr.Refused, ErrConflict) || replayErr.Err != nil {
+		t.Error("refused waiting command must be returned apart", err)
+	}
+	if queue, _ := fake.state(); fmt.Sprint(queue) != "[1 3 4 5]" || fake.calls["/playlist/add"] != 2 {
+		t.Error("new command must be sent once", queue, fake.calls["/playlist/add"])
+	}
+}
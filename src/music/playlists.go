@@ -0,0 +1,69 @@
+package music
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Playlists are lists of songs saved by user in a json file, by name
+type Playlists struct {
+	locker    sync.Mutex
+	path      string
+	playlists map[string][]Music
+}
+
+// LoadPlaylists read playlists file, a missing file has no playlist
+func LoadPlaylists(path string) (*Playlists, error) {
+	p := &Playlists{path: path, playlists: make(map[string][]Music)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	return p, json.Unmarshal(data, &p.playlists)
+}
+
+// Names return names of playlists, sorted
+func (p *Playlists) Names() []string {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	names := make([]string, 0, len(p.playlists))
+	for name := range p.playlists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Playlists) Get(name string) []Music {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	return append([]Music{}, p.playlists[name]...)
+}
+
+// Save create or replace a playlist
+func (p *Playlists) Save(name string, musics []Music) error {
+	if name == "" {
+		return errors.New("playlist must have a name")
+	}
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	p.playlists[name] = append([]Music{}, musics...)
+	return p.save()
+}
+
+func (p *Playlists) Delete(name string) error {
+	p.locker.Lock()
+	defer p.locker.Unlock()
+	delete(p.playlists, name)
+	return p.save()
+}
+
+func (p *Playlists) save() error {
+	return writeJSON(p.path, p.playlists)
+}
@@ -0,0 +1,31 @@
+package music
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPlaylists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "playlists.json")
+	playlists, err := LoadPlaylists(path)
+	if err != nil || len(playlists.Names()) != 0 {
+		t.Fatal("missing file must be empty", err)
+	}
+	playlists.Save("rock", []Music{fakeMusic(1), fakeMusic(2)})
+	playlists.Save("jazz", []Music{fakeMusic(3)})
+	if err = playlists.Save("", nil); err == nil {
+		t.Error("playlist without name must be rejected")
+	}
+	playlists.Delete("jazz")
+
+	loaded, err := LoadPlaylists(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := loaded.Names(); len(names) != 1 || names[0] != "rock" {
+		t.Error("bad playlists", names)
+	}
+	if musics := loaded.Get("rock"); len(musics) != 2 || musics[1].Id != "2" {
+		t.Error("bad songs", musics)
+	}
+}
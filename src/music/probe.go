@@ -0,0 +1,30 @@
+package music
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ProbeServer check that url is a reachable music server
+func ProbeServer(url string, client *http.Client) error {
+	return probe(fmt.Sprintf("%s/listByArtist", url), client)
+}
+
+// ProbePlayer check that url is a reachable music player
+func ProbePlayer(url string, client *http.Client) error {
+	return probe(fmt.Sprintf("%s/playlist/current", url), client)
+}
+
+func probe(url string, client *http.Client) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("bad status %d for %s", resp.StatusCode, url)
+	}
+	return nil
+}
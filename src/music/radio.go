@@ -0,0 +1,185 @@
+package music
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Number of random picks before giving up when filters exclude almost everything
+const radioAttempts = 50
+
+// RadioFilter restrict tracks played by radio, empty fields don't filter
+type RadioFilter struct {
+	// Only songs of these artists or albums
+	Artists []string `json:"artists,omitempty"`
+	Albums  []string `json:"albums,omitempty"`
+	// Never songs of these artists
+	ExcludedArtists []string `json:"excluded_artists,omitempty"`
+	// Years of release, 0 is no bound. Songs without year are excluded when a bound is set
+	FromYear int `json:"from_year,omitempty"`
+	ToYear   int `json:"to_year,omitempty"`
+	// Songs without genre are excluded when genres are set
+	Genres []string `json:"genres,omitempty"`
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match check if a song can be played
+func (f RadioFilter) Match(m Music) bool {
+	if len(f.Artists) > 0 && !containsFold(f.Artists, m.Artist) {
+		return false
+	}
+	if len(f.Albums) > 0 && !containsFold(f.Albums, m.Album) {
+		return false
+	}
+	if containsFold(f.ExcludedArtists, m.Artist) {
+		return false
+	}
+	if (f.FromYear > 0 || f.ToYear > 0) && m.Year == 0 {
+		return false
+	}
+	if (f.FromYear > 0 && m.Year < f.FromYear) || (f.ToYear > 0 && m.Year > f.ToYear) {
+		return false
+	}
+	return len(f.Genres) == 0 || containsFold(f.Genres, m.Genre)
+}
+
+// Radio pick random songs of the catalog. With the same seed and catalog, the same songs are picked
+type Radio struct {
+	locker sync.Mutex
+	mw     MusicWrapper
+	filter RadioFilter
+	random *rand.Rand
+	// Ids of artists or albums songs are picked from, sorted to be reproducible
+	sources []string
+	byAlbum bool
+	// Songs matching filter, by source
+	songs map[string][]Music
+	// Last picked ids, never picked again while they're in window
+	recent []string
+	window int
+}
+
+// NewRadio create a radio on catalog of mw. Songs stay out of the no repeat window during noRepeat picks
+func NewRadio(mw MusicWrapper, filter RadioFilter, seed int64, noRepeat int) *Radio {
+	r := &Radio{
+		mw:     mw,
+		filter: filter,
+		random: rand.New(rand.NewSource(seed)),
+		songs:  make(map[string][]Music),
+		window: noRepeat,
+	}
+	// Albums are smaller sources, they're used only when filter asks for some
+	index := mw.server.index()
+	dico := index.artistDico
+	if len(filter.Albums) > 0 {
+		dico, r.byAlbum = index.albumDico, true
+	}
+	for id, name := range dico {
+		if r.acceptSource(name) {
+			r.sources = append(r.sources, id)
+		}
+	}
+	sort.Strings(r.sources)
+	return r
+}
+
+// Skip sources which can't match, to avoid loading their songs
+func (r *Radio) acceptSource(name string) bool {
+	if r.byAlbum {
+		return containsFold(r.filter.Albums, name)
+	}
+	if len(r.filter.Artists) > 0 && !containsFold(r.filter.Artists, name) {
+		return false
+	}
+	return !containsFold(r.filter.ExcludedArtists, name)
+}
+
+// Songs of a source which match filter, loaded once. Sources which failed are loaded again next time
+func (r *Radio) songsOf(source string) ([]Music, error) {
+	if songs, exist := r.songs[source]; exist {
+		return songs, nil
+	}
+	var musics []*Music
+	var err error
+	if r.byAlbum {
+		musics, err = r.mw.ShowAlbum(Music{Id: source})
+	} else {
+		musics, err = r.mw.ShowArtist(Music{Id: source})
+	}
+	if err != nil {
+		return nil, err
+	}
+	songs := make([]Music, 0, len(musics))
+	for _, m := range musics {
+		if r.filter.Match(*m) {
+			songs = append(songs, *m)
+		}
+	}
+	r.songs[source] = songs
+	return songs, nil
+}
+
+func (r *Radio) isRecent(id string) bool {
+	for _, recent := range r.recent {
+		if recent == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Next pick count songs. Fewer songs are returned when filters and no repeat window leave too few songs.
+// When none, error of server is returned if songs couldn't be loaded, ErrNoCandidate otherwise
+func (r *Radio) Next(count int) ([]Music, error) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	var picked []Music
+	var lastErr error
+	for attempts := 0; len(picked) < count && attempts < radioAttempts*count && len(r.sources) > 0; attempts++ {
+		songs, err := r.songsOf(r.sources[r.random.Intn(len(r.sources))])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(songs) == 0 {
+			continue
+		}
+		song := songs[r.random.Intn(len(songs))]
+		if r.isRecent(song.Id) {
+			continue
+		}
+		picked = append(picked, song)
+		if r.window > 0 {
+			r.recent = remember(r.recent, song.Id, r.window)
+		}
+	}
+	if len(picked) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	if len(picked) == 0 {
+		return nil, ErrNoCandidate
+	}
+	return picked, nil
+}
+
+// Fill append songs to the queue of mw when fewer than minRemaining songs remain after the current one
+func (r *Radio) Fill(mw MusicWrapper, nowPlaying NowPlaying, minRemaining, count int) ([]Music, error) {
+	if nowPlaying.Size > 0 && nowPlaying.Size-nowPlaying.Index-1 >= minRemaining {
+		return nil, nil
+	}
+	picked, err := r.Next(count)
+	if err != nil {
+		return nil, err
+	}
+	return picked, mw.AddAll(picked)
+}
@@ -0,0 +1,76 @@
+package music
+
+import (
+	"testing"
+)
+
+func TestRadioFilter(t *testing.T) {
+	m := fakeMusic(4)
+	if !(RadioFilter{}).Match(m) {
+		t.Error("empty filter must match everything")
+	}
+	if !(RadioFilter{Artists: []string{"Artist 1"}, FromYear: 2004, ToYear: 2004, Genres: []string{"rock"}}).Match(m) {
+		t.Error("filter must match", m)
+	}
+	if (RadioFilter{ExcludedArtists: []string{"artist 1"}}).Match(m) {
+		t.Error("excluded artist must not match")
+	}
+	if (RadioFilter{FromYear: 1990}).Match(Music{Id: "1"}) {
+		t.Error("song without year must not match a year range")
+	}
+}
+
+func TestRadio(t *testing.T) {
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 30), player)
+
+	first, err := NewRadio(mw, RadioFilter{}, 42, 10).Next(20)
+	if err != nil || len(first) != 20 {
+		t.Fatal("radio must pick 20 songs", len(first), err)
+	}
+	second, _ := NewRadio(mw, RadioFilter{}, 42, 10).Next(20)
+	for i := range first {
+		if first[i].Id != second[i].Id {
+			t.Fatal("same seed must pick same songs", i)
+		}
+	}
+	for i := range first {
+		for j := i + 1; j < len(first) && j <= i+10; j++ {
+			if first[i].Id == first[j].Id {
+				t.Error("song repeated in no repeat window", first[i].Id, i, j)
+			}
+		}
+	}
+
+	filter := RadioFilter{ExcludedArtists: []string{"artist 0"}, FromYear: 2010, Genres: []string{"jazz"}}
+	songs, _ := NewRadio(mw, filter, 1, 0).Next(10)
+	for _, m := range songs {
+		if !filter.Match(m) {
+			t.Error("song doesn't match filter", m)
+		}
+	}
+
+	// Album 2 has 6 songs, a window of 10 can't be respected
+	radio := NewRadio(mw, RadioFilter{Albums: []string{"album 2"}}, 1, 10)
+	if songs, err := radio.Next(10); err != nil || len(songs) != 6 {
+		t.Error("only songs of album must be picked once", len(songs), err)
+	}
+	if _, err := radio.Next(1); err != ErrNoCandidate {
+		t.Error("all songs are in window", err)
+	}
+}
+
+func TestRadioFill(t *testing.T) {
+	fake, player := newFakePlayer(t, 1, 2)
+	mw := NewMusicWrapper(newFakeServer(t, 30), player)
+	radio := NewRadio(mw, RadioFilter{Artists: []string{"artist 2"}}, 1, 5)
+	if added, err := radio.Fill(mw, NowPlaying{Index: 0, Size: 2}, 2, 3); err != nil || len(added) != 3 {
+		t.Error("songs must be added", added, err)
+	}
+	if queue, _ := fake.state(); len(queue) != 5 {
+		t.Error("songs must be added to player", queue)
+	}
+	if added, _ := radio.Fill(mw, NowPlaying{Index: 0, Size: 5}, 2, 3); len(added) != 0 {
+		t.Error("nothing must be added while enough songs remain", added)
+	}
+}
@@ -0,0 +1,239 @@
+package music
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const MaxStars = 5
+
+// Rating is what user thinks of a music. Music is kept to find it again if server renumbers its ids
+type Rating struct {
+	Music    Music `json:"music"`
+	Favorite bool  `json:"favorite,omitempty"`
+	// 0 when not rated, else 1 to 5
+	Stars int `json:"stars,omitempty"`
+}
+
+// Ratings store favorites and stars in a json file, by id and by artist, album and title
+type Ratings struct {
+	locker sync.Mutex
+	path   string
+	byId   map[string]*Rating
+	byKey  map[string]*Rating
+}
+
+// Key used when id of music changed, empty if music has no title
+func fallbackKey(m Music) string {
+	if m.Title == "" {
+		return ""
+	}
+	return strings.ToLower(strings.Join([]string{m.Artist, m.Album, m.Title}, "\x00"))
+}
+
+// LoadRatings read ratings file, a missing file is an empty store
+func LoadRatings(path string) (*Ratings, error) {
+	r := &Ratings{path: path, byId: make(map[string]*Rating), byKey: make(map[string]*Rating)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return r, err
+	}
+	var ratings []Rating
+	if err = json.Unmarshal(data, &ratings); err != nil {
+		return r, err
+	}
+	for i := range ratings {
+		r.index(&ratings[i])
+	}
+	return r, nil
+}
+
+// When server renumbered its ids, another music may still own the id : its id is dropped, it stays reachable by its key
+func (r *Ratings) index(rating *Rating) {
+	key := fallbackKey(rating.Music)
+	if previous, exist := r.byId[rating.Music.Id]; exist && previous != rating {
+		if previousKey := fallbackKey(previous.Music); previousKey != "" && key != "" && previousKey != key {
+			previous.Music.Id = ""
+		}
+	}
+	if rating.Music.Id != "" {
+		r.byId[rating.Music.Id] = rating
+	}
+	if key != "" {
+		r.byKey[key] = rating
+	}
+}
+
+func (r *Ratings) unindex(rating *Rating) {
+	if r.byId[rating.Music.Id] == rating {
+		delete(r.byId, rating.Music.Id)
+	}
+	if key := fallbackKey(rating.Music); r.byKey[key] == rating {
+		delete(r.byKey, key)
+	}
+}
+
+// Id is trusted only if it's still the same music
+func (r *Ratings) find(m Music) *Rating {
+	key := fallbackKey(m)
+	if rating, exist := r.byId[m.Id]; exist && (key == "" || fallbackKey(rating.Music) == key) {
+		return rating
+	}
+	return r.byKey[key]
+}
+
+// Get return rating of a music, empty if not rated
+func (r *Ratings) Get(m Music) Rating {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	if rating := r.find(m); rating != nil {
+		return *rating
+	}
+	return Rating{Music: m}
+}
+
+func (r *Ratings) SetFavorite(m Music, favorite bool) error {
+	return r.update(m, func(rating *Rating) { rating.Favorite = favorite })
+}
+
+// Rate set stars of a music, 0 removes rating
+func (r *Ratings) Rate(m Music, stars int) error {
+	if stars < 0 || stars > MaxStars {
+		return fmt.Errorf("rating must be between 0 and %d", MaxStars)
+	}
+	return r.update(m, func(rating *Rating) { rating.Stars = stars })
+}
+
+func (r *Ratings) update(m Music, change func(*Rating)) error {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	rating := r.find(m)
+	if rating == nil {
+		rating = &Rating{Music: m}
+	} else {
+		r.unindex(rating)
+		// Keep new id and complete metadata
+		if m.Title != "" {
+			rating.Music = m
+		}
+		rating.Music.Id = m.Id
+	}
+	change(rating)
+	if rating.Favorite || rating.Stars > 0 {
+		r.index(rating)
+	}
+	return r.save()
+}
+
+// Ratings which lost their id are only indexed by key
+func (r *Ratings) all() []Rating {
+	distinct := make(map[*Rating]struct{}, len(r.byId))
+	for _, rating := range r.byId {
+		distinct[rating] = struct{}{}
+	}
+	for _, rating := range r.byKey {
+		distinct[rating] = struct{}{}
+	}
+	ratings := make([]Rating, 0, len(distinct))
+	for rating := range distinct {
+		ratings = append(ratings, *rating)
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		a, b := ratings[i].Music, ratings[j].Music
+		if a.Artist != b.Artist {
+			return a.Artist < b.Artist
+		}
+		if a.Album != b.Album {
+			return a.Album < b.Album
+		}
+		return a.Title < b.Title
+	})
+	return ratings
+}
+
+func (r *Ratings) save() error {
+	return writeJSON(r.path, r.all())
+}
+
+// Favorites return favorite musics sorted by artist, album and title
+func (r *Ratings) Favorites() []Rating {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	var favorites []Rating
+	for _, rating := range r.all() {
+		if rating.Favorite {
+			favorites = append(favorites, rating)
+		}
+	}
+	return favorites
+}
+
+// Rated return musics with at least minStars stars
+func (r *Ratings) Rated(minStars int) []Music {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	var musics []Music
+	for _, rating := range r.all() {
+		if rating.Stars >= minStars && rating.Stars > 0 {
+			musics = append(musics, rating.Music)
+		}
+	}
+	return musics
+}
+
+var ratingFilter = regexp.MustCompile(`(^|\s)rating(>=|>|=)([0-5])(\s|$)`)
+
+// ParseRatingFilter extract a filter like rating>=4 from a search term, return the term without it and the minimal and maximal stars
+func ParseRatingFilter(term string) (string, int, int, bool) {
+	match := ratingFilter.FindStringSubmatchIndex(term)
+	if match == nil {
+		return term, 0, 0, false
+	}
+	stars, _ := strconv.Atoi(term[match[6]:match[7]])
+	min, max := stars, MaxStars
+	switch term[match[4]:match[5]] {
+	case ">":
+		min = stars + 1
+	case "=":
+		max = stars
+	}
+	rest := strings.TrimSpace(term[:match[0]] + " " + term[match[1]:])
+	return rest, min, max, true
+}
+
+// WithRatings return a wrapper using ratings to filter searches
+func (mw MusicWrapper) WithRatings(ratings *Ratings) MusicWrapper {
+	mw.ratings = ratings
+	return mw
+}
+
+// Search songs with a rating filter : rated songs if there is no other term, otherwise filtered results of server
+func (mw MusicWrapper) searchRated(term string, min, max int) ([]Music, error) {
+	if mw.ratings == nil {
+		return []Music{}, nil
+	}
+	musics := mw.ratings.Rated(min)
+	if term != "" {
+		var err error
+		if musics, err = mw.server.Search(term); err != nil {
+			return nil, err
+		}
+	}
+	results := make([]Music, 0, len(musics))
+	for _, m := range musics {
+		if stars := mw.ratings.Get(m).Stars; stars >= min && stars <= max && stars > 0 {
+			results = append(results, m)
+		}
+	}
+	return results, nil
+}
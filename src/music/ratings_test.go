@@ -0,0 +1,113 @@
+package music
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRatings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	ratings, err := LoadRatings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ratings.Rate(fakeMusic(1), 5); err != nil {
+		t.Fatal(err)
+	}
+	ratings.SetFavorite(fakeMusic(2), true)
+	ratings.Rate(fakeMusic(3), 3)
+	if ratings.Rate(fakeMusic(3), 6) == nil {
+		t.Error("more than 5 stars must be rejected")
+	}
+
+	reloaded, err := LoadRatings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rating := reloaded.Get(fakeMusic(1)); rating.Stars != 5 {
+		t.Error("rating must be saved", rating)
+	}
+	if favorites := reloaded.Favorites(); len(favorites) != 1 || favorites[0].Music.Id != "2" {
+		t.Error("bad favorites", favorites)
+	}
+
+	// Server renumbered its musics : id 1 is now another song, first song is 10
+	renumbered := fakeMusic(1)
+	renumbered.Id = "10"
+	other := Music{Id: "1", Title: "new song", Artist: "new artist"}
+	if rating := reloaded.Get(renumbered); rating.Stars != 5 {
+		t.Error("music must be found by artist, album and title", rating)
+	}
+	if rating := reloaded.Get(other); rating.Favorite || rating.Stars != 0 {
+		t.Error("old id must not match another music", rating)
+	}
+	reloaded.Rate(renumbered, 4)
+	if rating := reloaded.Get(Music{Id: "10"}); rating.Stars != 4 {
+		t.Error("id must be updated", rating)
+	}
+	reloaded.Rate(renumbered, 0)
+	if rated := reloaded.Rated(1); len(rated) != 1 || rated[0].Id != "3" {
+		t.Error("removed rating must not be listed", rated)
+	}
+}
+
+func TestRenumberedIdCollision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	ratings, _ := LoadRatings(path)
+	ratings.Rate(fakeMusic(1), 5)
+
+	// Id 1 is given to a new song before old one is seen with its new id
+	other := Music{Id: "1", Title: "new song", Artist: "new artist"}
+	ratings.Rate(other, 3)
+	ratings.Rate(other, 2)
+	renumbered := fakeMusic(1)
+	renumbered.Id = "10"
+	if rating := ratings.Get(renumbered); rating.Stars != 5 {
+		t.Error("old rating must not be changed", rating)
+	}
+
+	reloaded, _ := LoadRatings(path)
+	if rating := reloaded.Get(other); rating.Stars != 2 {
+		t.Error("new song must keep its rating", rating)
+	}
+	if rating := reloaded.Get(renumbered); rating.Stars != 5 {
+		t.Error("old rating must be saved", rating)
+	}
+	reloaded.Rate(renumbered, 4)
+	if rating := reloaded.Get(Music{Id: "10"}); rating.Stars != 4 {
+		t.Error("old song must take its new id", rating)
+	}
+	if rating := reloaded.Get(other); rating.Stars != 2 {
+		t.Error("new song must not be changed", rating)
+	}
+}
+
+func TestParseRatingFilter(t *testing.T) {
+	for term, expected := range map[string]struct {
+		rest     string
+		min, max int
+		ok       bool
+	}{
+		"rating>=4":             {"", 4, 5, true},
+		"goldman rating>3":      {"goldman", 4, 5, true},
+		"rating=2 jean jacques": {"jean jacques", 2, 2, true},
+		"ratings>=4":            {"ratings>=4", 0, 0, false},
+	} {
+		rest, min, max, ok := ParseRatingFilter(term)
+		if rest != expected.rest || min != expected.min || max != expected.max || ok != expected.ok {
+			t.Error("bad filter for", term, rest, min, max, ok)
+		}
+	}
+}
+
+func TestSearchRated(t *testing.T) {
+	ratings, _ := LoadRatings(filepath.Join(t.TempDir(), "ratings.json"))
+	ratings.Rate(fakeMusic(1), 5)
+	ratings.Rate(fakeMusic(2), 3)
+	_, player := newFakePlayer(t)
+	mw := NewMusicWrapper(newFakeServer(t, 3), player).WithRatings(ratings)
+	results, kind, err := mw.HybridSearch("rating>=4")
+	if err != nil || kind != SongKind || len(results) != 1 || results[0].Id != "1" {
+		t.Error("only songs rated 4 or more must be found", results)
+	}
+}
@@ -0,0 +1,147 @@
+package music
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Room is a player identified by a name
+type Room struct {
+	Name   string
+	Player MusicPlayerWrapper
+}
+
+type Rooms []Room
+
+func (rooms Rooms) Get(name string) (Room, bool) {
+	for _, room := range rooms {
+		if room.Name == name {
+			return room, true
+		}
+	}
+	return Room{}, false
+}
+
+// Group return rooms with given names, to send them the same command
+func (rooms Rooms) Group(names ...string) Group {
+	group := make(Group, 0, len(names))
+	for _, name := range names {
+		if room, exist := rooms.Get(name); exist {
+			group = append(group, room)
+		}
+	}
+	return group
+}
+
+type Group []Room
+
+// Launch command on all players in parallel
+func (g Group) run(command func(MusicPlayerWrapper) error) error {
+	waiter := sync.WaitGroup{}
+	locker := sync.Mutex{}
+	var failures []string
+	for _, room := range g {
+		waiter.Add(1)
+		go func(r Room) {
+			defer waiter.Done()
+			if err := command(r.Player); err != nil {
+				locker.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", r.Name, err))
+				locker.Unlock()
+			}
+		}(room)
+	}
+	waiter.Wait()
+	if len(failures) > 0 {
+		return fmt.Errorf("command failed for %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (g Group) Pause() error {
+	return g.run(MusicPlayerWrapper.Pause)
+}
+
+func (g Group) UnPause() error {
+	return g.run(MusicPlayerWrapper.UnPause)
+}
+
+func (g Group) VolumeUp() error {
+	return g.run(MusicPlayerWrapper.VolumeUp)
+}
+
+func (g Group) VolumeDown() error {
+	return g.run(MusicPlayerWrapper.VolumeDown)
+}
+
+// NowPlaying describe what a player is playing
+type NowPlaying struct {
+	Index int
+	Size  int
+	// Empty when playlist is empty
+	Music Music
+}
+
+// Player return the controlled player
+func (mw MusicWrapper) Player() MusicPlayerWrapper {
+	return mw.player
+}
+
+// WithPlayer return a wrapper controlling another player with the same server
+func (mw MusicWrapper) WithPlayer(player MusicPlayerWrapper) MusicWrapper {
+	mw.player = player
+	// Edits of another queue can't be undone on this one, waiting commands were for the other player
+	mw.journal, mw.outbox = &Journal{}, &Outbox{}
+	return mw
+}
+
+func (mw MusicWrapper) NowPlaying(player MusicPlayerWrapper) (NowPlaying, error) {
+	ids, err := player.GetState()
+	if err != nil {
+		return NowPlaying{}, err
+	}
+	current, err := player.Current()
+	if err != nil {
+		return NowPlaying{}, err
+	}
+	nowPlaying := NowPlaying{Index: current, Size: len(ids)}
+	if current < 0 || current >= len(ids) {
+		return nowPlaying, nil
+	}
+	musics, err := mw.server.GetMusics([]int{ids[current]})
+	if err == nil && len(musics) > 0 {
+		nowPlaying.Music = musics[0]
+	}
+	return nowPlaying, err
+}
+
+// Transfer copy the queue of a player at the end of another one, play the current music on it and pause the first one
+func (mw MusicWrapper) Transfer(from, to MusicPlayerWrapper) error {
+	ids, err := from.GetState()
+	if err != nil {
+		return err
+	}
+	current, err := from.Current()
+	if err != nil {
+		return err
+	}
+	existing, err := to.GetState()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	musics := make([]*Music, len(ids))
+	for i, id := range ids {
+		musics[i] = &Music{Id: fmt.Sprintf("%d", id)}
+	}
+	if err := mw.WithPlayer(to).addMany(musics); err != nil {
+		return err
+	}
+	if err := to.Play(len(existing) + current); err != nil {
+		return err
+	}
+	return from.Pause()
+}
@@ -0,0 +1,49 @@
+package music
+
+import (
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	server := newFakeServer(t, 10)
+	living, livingPlayer := newFakePlayer(t, 1, 2, 3)
+	living.current = 1
+	office, officePlayer := newFakePlayer(t, 7)
+	mw := NewMusicWrapper(server, livingPlayer)
+
+	if err := mw.Transfer(livingPlayer, officePlayer); err != nil {
+		t.Fatal(err)
+	}
+	ids, current := office.state()
+	if len(ids) != 4 || ids[1] != 1 || ids[3] != 3 || current != 2 {
+		t.Error("bad office state", ids, current)
+	}
+	if !living.paused {
+		t.Error("source must be paused")
+	}
+}
+
+func TestNowPlaying(t *testing.T) {
+	server := newFakeServer(t, 10)
+	fake, player := newFakePlayer(t, 4, 5)
+	fake.current = 1
+	nowPlaying, err := NewMusicWrapper(server, player).NowPlaying(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nowPlaying.Size != 2 || nowPlaying.Music.Id != "5" {
+		t.Error("bad now playing", nowPlaying)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	first, firstPlayer := newFakePlayer(t)
+	second, secondPlayer := newFakePlayer(t)
+	rooms := Rooms{{"salon", firstPlayer}, {"bureau", secondPlayer}, {"cuisine", secondPlayer}}
+	if err := rooms.Group("salon", "bureau").VolumeUp(); err != nil {
+		t.Fatal(err)
+	}
+	if first.volume != 1 || second.volume != 1 {
+		t.Error("volume must be changed once on each player")
+	}
+}
@@ -0,0 +1,145 @@
+package music
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Period of statistics
+type Period int
+
+const (
+	WeekPeriod = Period(iota)
+	MonthPeriod
+	YearPeriod
+)
+
+// Range return start (included) and end (excluded) of period containing date, weeks start on monday
+func (p Period) Range(date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch p {
+	case WeekPeriod:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7)
+	case MonthPeriod:
+		start := day.AddDate(0, 0, 1-day.Day())
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start := time.Date(day.Year(), 1, 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(1, 0, 0)
+	}
+}
+
+// Count is the number of plays and listened time of an artist, an album or a track
+type Count struct {
+	Name     string        `json:"name"`
+	Plays    int           `json:"plays"`
+	Listened time.Duration `json:"listened"`
+}
+
+// Stats aggregate a listening history
+type Stats struct {
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Plays      int           `json:"plays"`
+	Total      time.Duration `json:"total"`
+	TopArtists []Count       `json:"top_artists"`
+	TopAlbums  []Count       `json:"top_albums"`
+	TopTracks  []Count       `json:"top_tracks"`
+	// Listened time by day of week (monday first) and hour of start
+	Heatmap [7][24]time.Duration `json:"heatmap"`
+}
+
+type counter struct {
+	counts map[string]*Count
+}
+
+func (c counter) add(key, name string, listened time.Duration) {
+	count, exist := c.counts[key]
+	if !exist {
+		count = &Count{Name: name}
+		c.counts[key] = count
+	}
+	count.Plays++
+	count.Listened += listened
+}
+
+// Most played first, then longest listened, then by name
+func (c counter) top(limit int) []Count {
+	counts := make([]Count, 0, len(c.counts))
+	for _, count := range c.counts {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Plays != counts[j].Plays {
+			return counts[i].Plays > counts[j].Plays
+		}
+		if counts[i].Listened != counts[j].Listened {
+			return counts[i].Listened > counts[j].Listened
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+// ComputeStats aggregate entries started between from and to, tops are limited to limit elements
+func ComputeStats(entries []HistoryEntry, from, to time.Time, limit int) Stats {
+	stats := Stats{From: from, To: to}
+	artists := counter{make(map[string]*Count)}
+	albums := counter{make(map[string]*Count)}
+	tracks := counter{make(map[string]*Count)}
+	for _, entry := range entries {
+		if entry.Start.Before(from) || !entry.Start.Before(to) {
+			continue
+		}
+		m := entry.Music
+		stats.Plays++
+		stats.Total += entry.Listened
+		artists.add(m.Artist, m.Artist, entry.Listened)
+		albums.add(m.Artist+"\x00"+m.Album, fmt.Sprintf("%s (%s)", m.Album, m.Artist), entry.Listened)
+		tracks.add(m.Id, fmt.Sprintf("%s - %s", m.Title, m.Artist), entry.Listened)
+		day := (int(entry.Start.Weekday()) + 6) % 7
+		stats.Heatmap[day][entry.Start.Hour()] += entry.Listened
+	}
+	stats.TopArtists, stats.TopAlbums, stats.TopTracks = artists.top(limit), albums.top(limit), tracks.top(limit)
+	return stats
+}
+
+func (s Stats) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteCSV write one line by aggregate : category, name, plays, listened seconds. Heatmap name is day-hour
+func (s Stats) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	seconds := func(d time.Duration) string {
+		return strconv.Itoa(int(d.Seconds()))
+	}
+	writer.Write([]string{"category", "name", "plays", "listened_seconds"})
+	writer.Write([]string{"total", "", strconv.Itoa(s.Plays), seconds(s.Total)})
+	for _, top := range []struct {
+		category string
+		counts   []Count
+	}{{"artist", s.TopArtists}, {"album", s.TopAlbums}, {"track", s.TopTracks}} {
+		for _, count := range top.counts {
+			writer.Write([]string{top.category, count.Name, strconv.Itoa(count.Plays), seconds(count.Listened)})
+		}
+	}
+	for day, hours := range s.Heatmap {
+		for hour, listened := range hours {
+			writer.Write([]string{"heatmap", fmt.Sprintf("%d-%02d", day, hour), "", seconds(listened)})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
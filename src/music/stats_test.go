@@ -0,0 +1,68 @@
+package music
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodRange(t *testing.T) {
+	// Wednesday
+	date := time.Date(2024, 3, 13, 15, 30, 0, 0, time.UTC)
+	if from, to := WeekPeriod.Range(date); from != time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC) || to != time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC) {
+		t.Error("week must start on monday", from, to)
+	}
+	if from, to := MonthPeriod.Range(date); from.Day() != 1 || to.Month() != time.April {
+		t.Error("bad month", from, to)
+	}
+	if from, to := YearPeriod.Range(date); from.YearDay() != 1 || to.Year() != 2025 {
+		t.Error("bad year", from, to)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	monday := time.Date(2024, 3, 11, 20, 0, 0, 0, time.UTC)
+	entry := func(id int, start time.Time, listened time.Duration) HistoryEntry {
+		return HistoryEntry{Music: fakeMusic(id), Start: start, Listened: listened}
+	}
+	entries := []HistoryEntry{
+		entry(1, monday, 3*time.Minute),
+		entry(4, monday.Add(3*time.Minute), 2*time.Minute),
+		entry(1, monday.Add(26*time.Hour), 3*time.Minute),
+		entry(2, monday.Add(26*time.Hour+3*time.Minute), time.Minute),
+		// Out of period
+		entry(2, monday.AddDate(0, 0, 7), time.Minute),
+	}
+	from, to := WeekPeriod.Range(monday)
+	stats := ComputeStats(entries, from, to, 2)
+	if stats.Plays != 4 || stats.Total != 9*time.Minute {
+		t.Error("bad total", stats.Plays, stats.Total)
+	}
+	if len(stats.TopArtists) != 2 || stats.TopArtists[0].Name != "artist 1" || stats.TopArtists[0].Plays != 3 {
+		t.Error("bad top artists", stats.TopArtists)
+	}
+	if stats.TopTracks[0].Name != "title 1 - artist 1" || stats.TopTracks[0].Listened != 6*time.Minute {
+		t.Error("bad top tracks", stats.TopTracks)
+	}
+	if stats.Heatmap[0][20] != 5*time.Minute || stats.Heatmap[1][22] != 4*time.Minute {
+		t.Error("bad heatmap", stats.Heatmap[0][20], stats.Heatmap[1][22])
+	}
+
+	buffer := bytes.Buffer{}
+	if err := stats.WriteCSV(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "artist,artist 1,3,480\n") || !strings.Contains(buffer.String(), "heatmap,0-20,,300\n") {
+		t.Error("bad csv", buffer.String())
+	}
+	buffer.Reset()
+	if err := stats.WriteJSON(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Stats
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil || decoded.Total != stats.Total {
+		t.Error("bad json", err)
+	}
+}
@@ -0,0 +1,56 @@
+package music
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttler launch action at most once per interval. First value is run at once, the last one given during interval at its end
+type Throttler[T any] struct {
+	locker   sync.Mutex
+	interval time.Duration
+	last     time.Time
+	pending  *T
+	timer    *time.Timer
+	action   func(T)
+}
+
+func NewThrottler[T any](interval time.Duration, action func(T)) *Throttler[T] {
+	return &Throttler[T]{interval: interval, action: action}
+}
+
+func (t *Throttler[T]) Input(value T) {
+	t.locker.Lock()
+	wait := t.interval - time.Since(t.last)
+	if t.timer == nil && wait <= 0 {
+		t.last = time.Now()
+		t.locker.Unlock()
+		t.action(value)
+		return
+	}
+	defer t.locker.Unlock()
+	t.pending = &value
+	if t.timer == nil {
+		t.timer = time.AfterFunc(wait, t.flush)
+	}
+}
+
+func (t *Throttler[T]) flush() {
+	t.locker.Lock()
+	pending := t.pending
+	t.pending, t.timer, t.last = nil, nil, time.Now()
+	t.locker.Unlock()
+	if pending != nil {
+		t.action(*pending)
+	}
+}
+
+// Stop cancel the pending action
+func (t *Throttler[T]) Stop() {
+	t.locker.Lock()
+	defer t.locker.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer, t.pending = nil, nil
+	}
+}
@@ -0,0 +1,36 @@
+package music
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottler(t *testing.T) {
+	values := make(chan int, 10)
+	throttler := NewThrottler(50*time.Millisecond, func(value int) { values <- value })
+	for i := 1; i <= 4; i++ {
+		throttler.Input(i)
+	}
+	if value := <-values; value != 1 {
+		t.Error("first value must be run at once, got", value)
+	}
+	select {
+	case value := <-values:
+		if value != 4 {
+			t.Error("last value must be run at end of interval, got", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("last value never run")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if len(values) != 0 {
+		t.Error("intermediate values must be dropped")
+	}
+	throttler.Input(5)
+	throttler.Input(6)
+	throttler.Stop()
+	time.Sleep(100 * time.Millisecond)
+	if len(values) != 1 {
+		t.Error("stopped throttler must drop pending value")
+	}
+}
@@ -0,0 +1,196 @@
+package music
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// TLSConfig describes how to trust an endpoint (server or player)
+type TLSConfig struct {
+	// PEM files added to the system roots
+	CAFiles []string `json:"ca_files,omitempty"`
+	// SHA-256 fingerprints of accepted leaf certificates. When set, only those certificates are accepted
+	PinnedFingerprints []string `json:"pinned_fingerprints,omitempty"`
+	// Client certificate and key used for mutual TLS
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	// Minimal TLS version : 1.0, 1.1, 1.2 (default) or 1.3
+	MinVersion string `json:"min_version,omitempty"`
+}
+
+// UntrustedCertificateError is returned when the certificate of an endpoint can't be verified.
+// Trust accepts the certificate for the next requests (trust on first use)
+type UntrustedCertificateError struct {
+	// Url of the endpoint the client was created for and its host
+	Endpoint    string
+	Host        string
+	Subject     string
+	Fingerprint string
+	cause       error
+	verifier    *certVerifier
+}
+
+func (e *UntrustedCertificateError) Error() string {
+	return fmt.Sprintf("untrusted certificate for %s (%s): %v", e.Host, e.Fingerprint, e.cause)
+}
+
+func (e *UntrustedCertificateError) Unwrap() error {
+	return e.cause
+}
+
+func (e *UntrustedCertificateError) Trust() {
+	e.verifier.pin(e.Fingerprint)
+}
+
+// Fingerprint return the SHA-256 fingerprint of a certificate, as colon separated hexa
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(fingerprint), " ", ""))
+}
+
+type certVerifier struct {
+	// Endpoint dialed by client, host is checked against certificate even when it's an ip address
+	endpoint     string
+	host         string
+	locker       sync.Mutex
+	roots        *x509.CertPool
+	fingerprints map[string]struct{}
+}
+
+func (v *certVerifier) pin(fingerprint string) {
+	v.locker.Lock()
+	defer v.locker.Unlock()
+	v.fingerprints[normalizeFingerprint(fingerprint)] = struct{}{}
+}
+
+// Return if some fingerprints are pinned and if the given one is part of them
+func (v *certVerifier) isPinned(fingerprint string) (bool, bool) {
+	v.locker.Lock()
+	defer v.locker.Unlock()
+	_, exist := v.fingerprints[fingerprint]
+	return len(v.fingerprints) > 0, exist
+}
+
+func (v *certVerifier) verify(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+	leaf := state.PeerCertificates[0]
+	fingerprint := Fingerprint(leaf)
+	host := v.host
+	if host == "" {
+		host = state.ServerName
+	}
+	untrusted := func(cause error) error {
+		return &UntrustedCertificateError{
+			Endpoint:    v.endpoint,
+			Host:        host,
+			Subject:     leaf.Subject.String(),
+			Fingerprint: fingerprint,
+			cause:       cause,
+			verifier:    v,
+		}
+	}
+	if hasPins, pinned := v.isPinned(fingerprint); hasPins {
+		if pinned {
+			return nil
+		}
+		return untrusted(errors.New("fingerprint not pinned"))
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         v.roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return untrusted(err)
+	}
+	return nil
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown tls version %s", version)
+}
+
+func loadRoots(caFiles []string) (*x509.CertPool, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	for _, file := range caFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if !roots.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", file)
+		}
+	}
+	return roots, nil
+}
+
+// NewHTTPClient creates a client of endpoint (its url) verifying certificates with the given configuration
+func NewHTTPClient(config TLSConfig, endpoint string) (*http.Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	minVersion, err := parseTLSVersion(config.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := loadRoots(config.CAFiles)
+	if err != nil {
+		return nil, err
+	}
+	verifier := &certVerifier{endpoint: endpoint, host: u.Hostname(), roots: roots, fingerprints: make(map[string]struct{})}
+	for _, fingerprint := range config.PinnedFingerprints {
+		verifier.pin(fingerprint)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: minVersion,
+		// Verification is done by the verifier to accept pinned certificates
+		InsecureSkipVerify: true,
+		VerifyConnection:   verifier.verify,
+	}
+	if config.ClientCert != "" || config.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,134 @@
+package music
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTLSServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+}
+
+func TestUntrustedThenTrusted(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+
+	client, err := NewHTTPClient(TLSConfig{}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Get(server.URL)
+	var untrusted *UntrustedCertificateError
+	if !errors.As(err, &untrusted) {
+		t.Fatalf("must be untrusted, got %v", err)
+	}
+	if untrusted.Fingerprint != Fingerprint(server.Certificate()) {
+		t.Error("bad fingerprint", untrusted.Fingerprint)
+	}
+	untrusted.Trust()
+	if _, err = client.Get(server.URL); err != nil {
+		t.Error("must be trusted after trust", err)
+	}
+}
+
+func TestCAFile(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewHTTPClient(TLSConfig{CAFiles: []string{caFile}, MinVersion: "1.2"}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Get(server.URL); err != nil {
+		t.Error("must be trusted with ca", err)
+	}
+}
+
+func TestPinnedFingerprint(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+
+	client, _ := NewHTTPClient(TLSConfig{PinnedFingerprints: []string{Fingerprint(server.Certificate())}}, server.URL)
+	if _, err := client.Get(server.URL); err != nil {
+		t.Error("must be trusted with pin", err)
+	}
+	client, _ = NewHTTPClient(TLSConfig{PinnedFingerprints: []string{"AA:BB"}}, server.URL)
+	if _, err := client.Get(server.URL); err == nil {
+		t.Error("must be rejected with another pin")
+	}
+}
+
+func TestBadTLSVersion(t *testing.T) {
+	if _, err := NewHTTPClient(TLSConfig{MinVersion: "2.0"}, "https://localhost"); err == nil {
+		t.Error("must reject unknown version")
+	}
+}
+
+// Certificate signed for another name, trusted as its own ca
+func otherNameCertificate(t *testing.T) (tls.Certificate, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other.example"},
+		DNSNames:              []string{"other.example"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestIPEndpointChecksName(t *testing.T) {
+	cert, caData := otherNameCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caData, 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewHTTPClient(TLSConfig{CAFiles: []string{caFile}}, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Get(server.URL)
+	var untrusted *UntrustedCertificateError
+	if !errors.As(err, &untrusted) {
+		t.Fatalf("certificate of another name must be untrusted, got %v", err)
+	}
+	if untrusted.Host != "127.0.0.1" || untrusted.Endpoint != server.URL {
+		t.Error("bad endpoint of error", untrusted.Host, untrusted.Endpoint)
+	}
+}
@@ -0,0 +1,77 @@
+package music
+
+import (
+	"sync"
+	"time"
+)
+
+// TrackWatcher detects when the current track of a player changes between two polls
+type TrackWatcher struct {
+	locker   sync.Mutex
+	last     NowPlaying
+	known    bool
+	onChange []func(previous, current NowPlaying)
+}
+
+func NewTrackWatcher() *TrackWatcher {
+	return &TrackWatcher{}
+}
+
+// OnChange add a listener, called with previous and new track. On first poll or after a reset, previous is empty
+func (tw *TrackWatcher) OnChange(listener func(previous, current NowPlaying)) {
+	tw.locker.Lock()
+	defer tw.locker.Unlock()
+	tw.onChange = append(tw.onChange, listener)
+}
+
+func changed(previous, current NowPlaying) bool {
+	if previous.Music.Id != current.Music.Id {
+		return true
+	}
+	// Same song played twice in a row, an index moved by a removal isn't a change
+	return previous.Index != current.Index && previous.Size == current.Size
+}
+
+// Update compare state of player with the previous one and call listeners if track changed
+func (tw *TrackWatcher) Update(nowPlaying NowPlaying) bool {
+	tw.locker.Lock()
+	previous, known := tw.last, tw.known
+	tw.last, tw.known = nowPlaying, true
+	listeners := tw.onChange
+	tw.locker.Unlock()
+	if known && !changed(previous, nowPlaying) {
+		return false
+	}
+	for _, listener := range listeners {
+		listener(previous, nowPlaying)
+	}
+	return true
+}
+
+// Reset forget the last track, used when watched player changes
+func (tw *TrackWatcher) Reset() {
+	tw.locker.Lock()
+	defer tw.locker.Unlock()
+	tw.last, tw.known = NowPlaying{}, false
+}
+
+// Watch poll at each interval until stop is closed. Failed polls are ignored
+func (tw *TrackWatcher) Watch(interval time.Duration, stop <-chan struct{}, poll func() (NowPlaying, error)) {
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+	for {
+		if nowPlaying, err := poll(); err == nil {
+			tw.Update(nowPlaying)
+		}
+		select {
+		case <-stop:
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// CurrentTrack return what the controlled player plays
+func (mw MusicWrapper) CurrentTrack() (NowPlaying, error) {
+	return mw.NowPlaying(mw.player)
+}
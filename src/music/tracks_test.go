@@ -0,0 +1,42 @@
+package music
+
+import (
+	"testing"
+)
+
+func TestTrackWatcher(t *testing.T) {
+	watcher := NewTrackWatcher()
+	var changes []NowPlaying
+	watcher.OnChange(func(previous, current NowPlaying) {
+		changes = append(changes, current)
+	})
+	fake, player := newFakePlayer(t, 1, 1, 2)
+	mw := NewMusicWrapper(newFakeServer(t, 3), player)
+	update := func() bool {
+		nowPlaying, err := mw.CurrentTrack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return watcher.Update(nowPlaying)
+	}
+
+	if !update() || changes[0].Music.Id != "1" {
+		t.Fatal("first poll must be notified", changes)
+	}
+	if update() {
+		t.Error("same track must not be notified")
+	}
+	fake.set(fake.ids, 1)
+	if !update() || changes[1].Index != 1 {
+		t.Error("same song played again must be notified", changes)
+	}
+	fake.set(fake.ids, 2)
+	if !update() || changes[2].Music.Id != "2" {
+		t.Error("next track must be notified", changes)
+	}
+	// Removal of first song moves index of current one
+	fake.set(fake.ids[1:], 1)
+	if update() {
+		t.Error("moved track must not be notified")
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"github.com/jotitan/fyne_poc/src/tui"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exit codes
+const (
+	exitOk      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+var errUsage = errors.New("bad usage")
+
+const usage = `Usage: musicctl [flags] <command> [arguments]
+
+Commands:
+  search TERM...       search songs, ":artist NAME" or ":album NAME" search artists or albums
+  add ID               add a song to the queue
+  add-artist ID        add all songs of an artist
+  add-album ID         add all songs of an album
+  queue                print the queue, current song is marked with *
+  play N               play song at position N of the queue
+  pause | resume       pause or resume the player
+  next | prev          play next or previous song
+  vol up|down [COUNT]  change volume
+  rm N                 remove song at position N of the queue
+  tui                  full screen terminal interface
+
+Flags:
+`
+
+type cli struct {
+	wrapper music.MusicWrapper
+	conf    config.Config
+	asJson  bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("musicctl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	overrides := config.RegisterFlags(flags)
+	asJson := flags.Bool("json", false, "print results as json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	conf, _, err := overrides.Load()
+	if err == nil {
+		err = conf.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configuration:", err)
+		return exitUsage
+	}
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	// Artists and albums index is only needed to search them
+	needsIndex := command == "tui" || (command == "search" && len(commandArgs) > 0 && strings.HasPrefix(commandArgs[0], ":"))
+	c, err := newCli(conf, needsIndex, *asJson)
+	if err == nil {
+		err = c.execute(command, commandArgs)
+	}
+	switch {
+	case err == nil:
+		return exitOk
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, err)
+		flags.Usage()
+		return exitUsage
+	default:
+		printError(err, *asJson)
+		return exitFailure
+	}
+}
+
+func newCli(conf config.Config, needsIndex bool, asJson bool) (cli, error) {
+	profile := conf.Current()
+	serverClient, playerClient, err := profile.Clients(conf.Timeout)
+	if err != nil {
+		return cli{}, err
+	}
+	server := music.NewLightMusicServerWrapper(profile.ServerURL, serverClient)
+	if needsIndex {
+		if server, err = music.NewMusicServerWrapperWithClient(profile.ServerURL, serverClient); err != nil {
+			return cli{}, err
+		}
+	}
+	player := music.NewMusicPlayerWrapperWithClient(profile.PlayerURL, playerClient)
+	return cli{music.NewMusicWrapper(server, player), conf, asJson}, nil
+}
+
+func printError(err error, asJson bool) {
+	if asJson {
+		json.NewEncoder(os.Stderr).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	fmt.Fprintln(os.Stderr, "error:", err)
+}
+
+func usageError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+func expectArgs(args []string, count int) error {
+	if len(args) != count {
+		return usageError("expected %d argument(s), got %d", count, len(args))
+	}
+	return nil
+}
+
+// Return position (starting at 1) given by user
+func parsePosition(value string) (int, error) {
+	position, err := strconv.Atoi(value)
+	if err != nil || position < 1 {
+		return 0, usageError("bad position %s", value)
+	}
+	return position, nil
+}
+
+func (c cli) execute(command string, args []string) error {
+	switch command {
+	case "search":
+		if len(args) == 0 {
+			return usageError("search needs a term")
+		}
+		return c.search(strings.Join(args, " "))
+	case "add", "add-artist", "add-album":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		return c.add(command, args[0])
+	case "queue":
+		return c.queue()
+	case "play":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		position, err := parsePosition(args[0])
+		if err != nil {
+			return err
+		}
+		return c.done(c.wrapper.Play(position - 1))
+	case "rm":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		position, err := parsePosition(args[0])
+		if err != nil {
+			return err
+		}
+		// Only position is known, id is read from queue
+		entry, err := c.wrapper.EntryAt(position)
+		if err != nil {
+			return err
+		}
+		return c.done(c.wrapper.Delete(entry))
+	case "pause":
+		return c.done(c.wrapper.Pause())
+	case "resume":
+		return c.done(c.wrapper.UnPause())
+	case "next":
+		return c.done(c.wrapper.Next())
+	case "prev":
+		return c.done(c.wrapper.Previous())
+	case "vol":
+		return c.volume(args)
+	case "tui":
+		i18n.SetLanguage(c.conf.Language)
+		title := i18n.T("window.room", c.conf.Current().Name)
+		return tui.NewApp(c.wrapper, title, time.Duration(c.conf.PollInterval)).Run()
+	}
+	return usageError("unknown command %s", command)
+}
+
+// Print result of an action without output
+func (c cli) done(err error) error {
+	if err != nil {
+		return err
+	}
+	if c.asJson {
+		return json.NewEncoder(os.Stdout).Encode(map[string]string{"status": "ok"})
+	}
+	return nil
+}
+
+func (c cli) volume(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return usageError("vol needs up or down")
+	}
+	count := 1
+	if len(args) == 2 {
+		var err error
+		if count, err = parsePosition(args[1]); err != nil {
+			return err
+		}
+	}
+	change := c.wrapper.VolumeUp
+	switch args[0] {
+	case "up":
+	case "down":
+		change = c.wrapper.VolumeDown
+	default:
+		return usageError("vol needs up or down, not %s", args[0])
+	}
+	for i := 0; i < count; i++ {
+		if err := change(); err != nil {
+			return err
+		}
+	}
+	return c.done(nil)
+}
+
+func (c cli) search(term string) error {
+	results, kind, err := c.wrapper.HybridSearch(term)
+	if err != nil {
+		return err
+	}
+	if c.asJson {
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"kind": kind, "results": results})
+	}
+	for _, m := range results {
+		if kind == music.SongKind {
+			fmt.Printf("%s\t%s - %s (%s)\n", m.Id, m.Title, m.Artist, m.Album)
+		} else {
+			fmt.Printf("%s\t%s\n", m.Id, m.Artist)
+		}
+	}
+	return nil
+}
+
+func (c cli) add(command, id string) error {
+	m := music.Music{Id: id}
+	switch command {
+	case "add-artist":
+		return c.done(c.wrapper.AddAllArtist(m))
+	case "add-album":
+		return c.done(c.wrapper.AddAllAlbum(m))
+	}
+	return c.done(c.wrapper.Add(m))
+}
+
+func (c cli) queue() error {
+	musics, err := c.wrapper.GetPlaylist()
+	if err != nil {
+		return err
+	}
+	current, err := c.wrapper.Current()
+	if err != nil {
+		return err
+	}
+	if c.asJson {
+		return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"current": current + 1, "musics": musics})
+	}
+	for i, m := range musics {
+		marker := " "
+		if i == current {
+			marker = "*"
+		}
+		fmt.Printf("%s %3d  %s - %s (%s)\n", marker, i+1, m.Title, m.Artist, m.Album)
+	}
+	return nil
+}
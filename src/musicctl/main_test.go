@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Player which only counts calls
+type fakePlayer struct {
+	locker sync.Mutex
+	calls  map[string]int
+}
+
+func (f *fakePlayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	f.calls[r.URL.Path]++
+	if r.URL.Path == "/playlist/state" {
+		fmt.Fprint(w, `{"current":0,"size":0,"ids":[]}`)
+	}
+}
+
+func (f *fakePlayer) count(path string) int {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+	return f.calls[path]
+}
+
+func newServer(t *testing.T, handler http.HandlerFunc) string {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRun(t *testing.T) {
+	player := &fakePlayer{calls: make(map[string]int)}
+	playerURL := newServer(t, player.ServeHTTP)
+	serverURL := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			json.NewEncoder(w).Encode([]map[string]string{{"id": "1", "title": "Title 1"}})
+		case "/listByArtist":
+			json.NewEncoder(w).Encode([]map[string]string{{"id": "1", "name": "Title 1"}})
+		case "/pathOfMusic":
+			fmt.Fprint(w, "/music/1.mp3")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	failingURL := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	for _, test := range []struct {
+		name   string
+		server string
+		args   []string
+		code   int
+	}{
+		{"no command", serverURL, nil, exitUsage},
+		{"unknown command", serverURL, []string{"dance"}, exitUsage},
+		{"bad position", serverURL, []string{"play", "first"}, exitUsage},
+		{"search", serverURL, []string{"search", "title"}, exitOk},
+		{"failed search", failingURL, []string{"search", "title"}, exitFailure},
+		{"add artist", serverURL, []string{"add-artist", "artist=1"}, exitOk},
+		{"failed add artist", failingURL, []string{"add-artist", "artist=1"}, exitFailure},
+		{"failed add album", failingURL, []string{"add-album", "album=1"}, exitFailure},
+		{"next", serverURL, []string{"next"}, exitOk},
+	} {
+		args := append([]string{"-config", configPath, "-server", test.server, "-player", playerURL}, test.args...)
+		if code := run(args); code != test.code {
+			t.Errorf("%s : exit code must be %d, got %d", test.name, test.code, code)
+		}
+	}
+	if adds, nexts := player.count("/playlist/add"), player.count("/music/next"); adds != 1 || nexts != 1 {
+		t.Error("only successful commands must reach player", adds, nexts)
+	}
+}
@@ -0,0 +1,35 @@
+package panel
+
+import (
+	"errors"
+	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
+)
+
+// Number of tracks appended each time the queue runs dry
+const autoDJBatchSize = 5
+
+// Append tracks to the queue when auto DJ is enabled and few tracks remain. Recent tracks are always remembered to avoid repeats
+func (mp *MusicPanel) autoContinue(watcher *music.TrackWatcher) {
+	settings := mp.settings().AutoDJ
+	dj := music.NewAutoDJ(settings.DJStrategy(), settings.MinRemaining, autoDJBatchSize)
+	// Listeners are called one by one by the watcher, dj is only changed here. Settings are read at each change as they can be saved meanwhile
+	watcher.OnChange(func(_, current music.NowPlaying) {
+		settings := mp.settings().AutoDJ
+		// Radio already feeds the queue
+		if !settings.Enabled || mp.currentRadio() != nil {
+			dj.Played(current.Music)
+			return
+		}
+		dj.Strategy, dj.MinRemaining = settings.DJStrategy(), settings.MinRemaining
+		added, err := dj.Fill(mp.Wrapper(), current)
+		switch {
+		case errors.Is(err, music.ErrNoCandidate):
+			slog.Debug("auto DJ has nothing to add")
+		case err != nil:
+			slog.Warn("auto DJ failed", "err", err)
+		case len(added) > 0:
+			mp.updateChanel <- struct{}{}
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/theme"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"strings"
+	"sync"
+)
+
+// Page of library browser with the title shown in breadcrumbs
+type browsePage struct {
+	title   string
+	content fyne.CanvasObject
+}
+
+// libraryBrowser is a back stack of pages opened from search results, which are the first page
+type libraryBrowser struct {
+	locker sync.Mutex
+	pages  []browsePage
+	crumbs *fyne.Container
+	view   *fyne.Container
+}
+
+func newLibraryBrowser(results fyne.CanvasObject) *libraryBrowser {
+	b := &libraryBrowser{
+		pages:  []browsePage{{title: i18n.T("browser.search"), content: results}},
+		crumbs: container.NewHBox(),
+		view:   container.NewMax(results),
+	}
+	b.show()
+	return b
+}
+
+func (b *libraryBrowser) push(title string, content fyne.CanvasObject) {
+	b.locker.Lock()
+	b.pages = append(b.pages, browsePage{title: title, content: content})
+	b.locker.Unlock()
+	b.show()
+}
+
+// Go back to page at level, 0 is search results
+func (b *libraryBrowser) popTo(level int) {
+	b.locker.Lock()
+	if level < 0 || level >= len(b.pages)-1 {
+		b.locker.Unlock()
+		return
+	}
+	b.pages = b.pages[:level+1]
+	b.locker.Unlock()
+	b.show()
+}
+
+func (b *libraryBrowser) back() {
+	b.locker.Lock()
+	level := len(b.pages) - 2
+	b.locker.Unlock()
+	b.popTo(level)
+}
+
+func (b *libraryBrowser) show() {
+	b.locker.Lock()
+	pages := append([]browsePage{}, b.pages...)
+	b.locker.Unlock()
+	back := widget.NewButtonWithIcon("", theme.NavigateBackIcon(), b.back)
+	if len(pages) == 1 {
+		back.Disable()
+	}
+	crumbs := []fyne.CanvasObject{back}
+	for level, page := range pages {
+		if level > 0 {
+			crumbs = append(crumbs, widget.NewLabel("›"))
+		}
+		if level == len(pages)-1 {
+			current := widget.NewLabel(page.title)
+			current.TextStyle = fyne.TextStyle{Bold: true}
+			crumbs = append(crumbs, current)
+			break
+		}
+		target := level
+		crumb := widget.NewButton(page.title, func() { b.popTo(target) })
+		crumb.Importance = widget.LowImportance
+		crumbs = append(crumbs, crumb)
+	}
+	b.crumbs.Objects = crumbs
+	b.crumbs.Refresh()
+	b.view.Objects = []fyne.CanvasObject{pages[len(pages)-1].content}
+	b.view.Refresh()
+}
+
+// Open page of an artist or an album returned by search, on top of search results
+func (mp *MusicPanel) openPage(browser *libraryBrowser, m music.Music, kind music.Kind) {
+	browser.popTo(0)
+	switch kind {
+	case music.ArtistKind:
+		browser.push(m.Artist, mp.artistPage(browser, m))
+	case music.AlbumKind:
+		browser.push(m.Album, mp.albumPage(m.Album, func() ([]music.Music, error) { return mp.Wrapper().Tracklist(m) }))
+	}
+}
+
+// Header of a page with add and play actions on all its songs
+func (mp *MusicPanel) pageHeader(title string, songs func() []music.Music) fyne.CanvasObject {
+	label := widget.NewLabel(title)
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	return container.NewHBox(label, layout.NewSpacer(),
+		widget.NewButton(i18n.T("action.add_all"), func() { go mp.addAll(songs()) }),
+		widget.NewButtonWithIcon(i18n.T("action.play"), theme.MediaPlayIcon(), func() { go mp.playSongs(songs()) }))
+}
+
+// Albums of an artist, oldest first
+func (mp *MusicPanel) artistPage(browser *libraryBrowser, artist music.Music) fyne.CanvasObject {
+	locker := sync.Mutex{}
+	var albums []music.Album
+	songs := func() []music.Music {
+		locker.Lock()
+		defer locker.Unlock()
+		var all []music.Music
+		for _, album := range albums {
+			all = append(all, album.Songs...)
+		}
+		return all
+	}
+	status := widget.NewLabel(i18n.T("browser.loading"))
+	list := widget.NewList(
+		func() int {
+			locker.Lock()
+			defer locker.Unlock()
+			return len(albums)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("album")
+			name.TextStyle = fyne.TextStyle{Bold: true}
+			return container.NewHBox(
+				container.NewVBox(name, widget.NewLabel("")),
+				layout.NewSpacer(),
+				widget.NewButton(i18n.T("action.show"), func() {}),
+				widget.NewButton(i18n.T("action.add"), func() {}),
+				widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			locker.Lock()
+			if i >= len(albums) {
+				locker.Unlock()
+				return
+			}
+			album := albums[i]
+			locker.Unlock()
+			fields := o.(*fyne.Container).Objects
+			labels := fields[0].(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(album.Name)
+			labels[1].(*widget.Label).SetText(albumDetails(album))
+			fields[2].(*widget.Button).OnTapped = func() {
+				browser.push(album.Name, mp.albumPage(album.Name, func() ([]music.Music, error) { return album.Songs, nil }))
+			}
+			fields[3].(*widget.Button).OnTapped = func() { go mp.addAll(album.Songs) }
+			fields[4].(*widget.Button).OnTapped = func() { go mp.playSongs(album.Songs) }
+		})
+	var load func()
+	load = func() {
+		result, err := mp.Wrapper().AlbumsOf(artist)
+		if err != nil {
+			status.SetText(i18n.T("error.load"))
+			mp.reportError(i18n.T("error.load"), err, func() { go load() })
+			return
+		}
+		locker.Lock()
+		albums = result
+		locker.Unlock()
+		status.SetText(i18n.N("browser.albums", len(result)))
+		list.Refresh()
+	}
+	go load()
+	header := container.NewVBox(mp.pageHeader(artist.Artist, songs), status)
+	return fyne.NewContainerWithLayout(layout.NewBorderLayout(header, nil, nil, nil), header, list)
+}
+
+// Year and number of songs of an album
+func albumDetails(album music.Album) string {
+	details := []string{i18n.N("browser.tracks", len(album.Songs))}
+	if album.Year != 0 {
+		details = append([]string{fmt.Sprintf("%d", album.Year)}, details...)
+	}
+	return strings.Join(details, " · ")
+}
+
+// Tracklist of an album, load is called in background, again when user retries after an error
+func (mp *MusicPanel) albumPage(title string, load func() ([]music.Music, error)) fyne.CanvasObject {
+	locker := sync.Mutex{}
+	var songs []music.Music
+	current := func() []music.Music {
+		locker.Lock()
+		defer locker.Unlock()
+		return songs
+	}
+	status := widget.NewLabel(i18n.T("browser.loading"))
+	list := widget.NewList(
+		func() int {
+			return len(current())
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				widget.NewLabel("track"),
+				layout.NewSpacer(),
+				widget.NewLabel("0:00"),
+				widget.NewButton(i18n.T("action.add"), func() {}),
+				widget.NewButtonWithIcon("", theme.MediaPlayIcon(), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			tracks := current()
+			if i >= len(tracks) {
+				return
+			}
+			song := tracks[i]
+			fields := o.(*fyne.Container).Objects
+			position := song.Track
+			if position == 0 {
+				position = i + 1
+			}
+			fields[0].(*widget.Label).SetText(fmt.Sprintf("%d. %s", position, song.Title))
+			fields[2].(*widget.Label).SetText("")
+			if song.Length > 0 {
+				fields[2].(*widget.Label).SetText(formatDuration(song.Duration()))
+			}
+			fields[3].(*widget.Button).OnTapped = func() { go mp.addAll([]music.Music{song}) }
+			fields[4].(*widget.Button).OnTapped = func() { go mp.playSongs([]music.Music{song}) }
+		})
+	var fetch func()
+	fetch = func() {
+		result, err := load()
+		if err != nil {
+			status.SetText(i18n.T("error.load"))
+			mp.reportError(i18n.T("error.load"), err, func() { go fetch() })
+			return
+		}
+		locker.Lock()
+		songs = result
+		locker.Unlock()
+		status.SetText(i18n.N("browser.tracks", len(result)))
+		list.Refresh()
+	}
+	go fetch()
+	header := container.NewVBox(mp.pageHeader(title, current), status)
+	return fyne.NewContainerWithLayout(layout.NewBorderLayout(header, nil, nil, nil), header, list)
+}
+
+// Append songs and play the first one
+func (mp *MusicPanel) playSongs(songs []music.Music) {
+	if len(songs) == 0 {
+		return
+	}
+	if err := mp.Wrapper().AddAndPlay(songs); err != nil {
+		mp.reportError(i18n.T("error.play"), err, func() { go mp.playSongs(songs) })
+		return
+	}
+	mp.updateChanel <- struct{}{}
+}
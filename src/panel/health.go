@@ -0,0 +1,85 @@
+package panel
+
+import (
+	"fyne.io/fyne"
+	"fyne.io/fyne/canvas"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"image/color"
+	"log/slog"
+	"time"
+)
+
+var healthColors = map[music.HealthState]color.Color{
+	music.Connected: color.NRGBA{R: 0x2e, G: 0xa0, B: 0x43, A: 0xff},
+	music.Degraded:  color.NRGBA{R: 0xe8, G: 0x9b, B: 0x1a, A: 0xff},
+	music.Offline:   color.NRGBA{R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+}
+
+// Keys of labels in catalog
+var healthLabels = map[music.HealthState]string{
+	music.Connected: "health.connected",
+	music.Degraded:  "health.degraded",
+	music.Offline:   "health.offline",
+}
+
+// statusBadge is a toolbar item showing state of connection with a colored dot
+type statusBadge struct {
+	dot    *canvas.Circle
+	label  *widget.Label
+	object fyne.CanvasObject
+}
+
+func newStatusBadge() *statusBadge {
+	badge := &statusBadge{dot: canvas.NewCircle(healthColors[music.Connected]), label: widget.NewLabel("...")}
+	badge.object = container.NewHBox(container.NewCenter(container.NewGridWrap(fyne.NewSize(12, 12), badge.dot)), badge.label)
+	return badge
+}
+
+func (b *statusBadge) ToolbarObject() fyne.CanvasObject {
+	return b.object
+}
+
+func (b *statusBadge) show(health music.Health) {
+	b.dot.FillColor = healthColors[health.State]
+	b.dot.Refresh()
+	key := healthLabels[health.State]
+	if health.State == music.Offline {
+		b.label.SetText(i18n.T(key))
+		return
+	}
+	b.label.SetText(i18n.T(key, health.Player.Latency.Milliseconds()))
+}
+
+// Probe server and player of the controlled room, when they answer again index and queue are reloaded
+func (mp *MusicPanel) monitorHealth(stop chan struct{}) {
+	monitor := music.NewHealthMonitor(
+		func() error { return mp.Wrapper().PingServer() },
+		func() error { return mp.Wrapper().PingPlayer() })
+	monitor.OnChange(func(previous, current music.Health) {
+		slog.Info("connection changed", "state", current.State, "health", current.Description())
+		if current.ServerRecovered(previous) {
+			if err := mp.Wrapper().ReloadIndex(); err != nil {
+				slog.Error("index not reloaded", "err", err)
+			}
+		}
+		if current.ServerRecovered(previous) || current.PlayerRecovered(previous) {
+			mp.replayPending()
+			mp.updateChanel <- struct{}{}
+		}
+	})
+	go func() {
+		timer := time.NewTicker(time.Duration(mp.settings().PollInterval))
+		defer timer.Stop()
+		for {
+			mp.statusBadge.show(monitor.Check())
+			select {
+			case <-stop:
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package panel
+
+import (
+	"errors"
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const historyDateFormat = "2006-01-02 15:04"
+
+// Record tracks seen by watcher until profile changes or window is closed
+func (mp *MusicPanel) recordHistory(watcher *music.TrackWatcher, stop chan struct{}) {
+	recorder := music.NewHistoryRecorder(mp.history, func() string { return mp.room() })
+	watcher.OnChange(recorder.Changed)
+	closeRecorder := func() {
+		if err := recorder.Close(); err != nil {
+			slog.Error("history not saved", "err", err)
+		}
+	}
+	mp.win.SetOnClosed(closeRecorder)
+	go func() {
+		<-stop
+		closeRecorder()
+	}()
+}
+
+// ShowHistory list played tracks between two dates, to add them again to the queue
+func (mp *MusicPanel) ShowHistory() {
+	win := mp.app.NewWindow(i18n.T("history.title"))
+	locker := sync.Mutex{}
+	var entries []music.HistoryEntry
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	from := widget.NewEntry()
+	from.SetText(today.Format(historyDateFormat))
+	to := widget.NewEntry()
+	to.SetText(today.AddDate(0, 0, 1).Format(historyDateFormat))
+
+	list := widget.NewList(
+		func() int {
+			locker.Lock()
+			defer locker.Unlock()
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				widget.NewLabel("entry"),
+				layout.NewSpacer(),
+				widget.NewButton(i18n.T("action.add"), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			locker.Lock()
+			defer locker.Unlock()
+			if i >= len(entries) {
+				return
+			}
+			entry := entries[i]
+			fields := o.(*fyne.Container).Objects
+			fields[0].(*widget.Label).SetText(fmt.Sprintf("%s - %s - %s (%s, %s)",
+				entry.Start.Format(historyDateFormat), entry.Music.Title, entry.Music.Artist, entry.Player, formatDuration(entry.Listened)))
+			fields[2].(*widget.Button).OnTapped = func() {
+				mp.add(entry.Music, music.SongKind)
+			}
+		})
+
+	filter := func() {
+		start, errFrom := time.ParseInLocation(historyDateFormat, from.Text, time.Local)
+		end, errTo := time.ParseInLocation(historyDateFormat, to.Text, time.Local)
+		if errFrom != nil || errTo != nil {
+			dialog.ShowError(errors.New(i18n.T("history.bad_date", historyDateFormat)), win)
+			return
+		}
+		found, err := mp.history.Read(start, end)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		locker.Lock()
+		entries = found
+		locker.Unlock()
+		list.Refresh()
+	}
+	addAll := func() {
+		locker.Lock()
+		musics := make([]music.Music, len(entries))
+		for i, entry := range entries {
+			musics[i] = entry.Music
+		}
+		locker.Unlock()
+		go func() {
+			if err := mp.Wrapper().AddAll(musics); err != nil {
+				dialog.ShowError(err, win)
+				return
+			}
+			mp.updateChanel <- struct{}{}
+		}()
+	}
+
+	filters := container.NewHBox(
+		widget.NewLabel(i18n.T("history.from")), from,
+		widget.NewLabel(i18n.T("history.to")), to,
+		widget.NewButton(i18n.T("history.filter"), filter),
+		layout.NewSpacer(),
+		widget.NewButton(i18n.T("action.add_all"), addAll))
+	filter()
+
+	win.SetContent(fyne.NewContainerWithLayout(layout.NewBorderLayout(filters, nil, nil, nil), filters, list))
+	win.Resize(fyne.Size{Width: 700, Height: 500})
+	win.Show()
+}
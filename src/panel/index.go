@@ -0,0 +1,99 @@
+package panel
+
+import (
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"sync"
+)
+
+// ShowIndex list every artist or album in alphabetical order, a letter bar jumps to the first name of a letter
+func (mp *MusicPanel) ShowIndex() {
+	win := mp.app.NewWindow(i18n.T("index.title"))
+	locker := sync.Mutex{}
+	var index music.Index
+
+	list := widget.NewList(
+		func() int {
+			locker.Lock()
+			defer locker.Unlock()
+			return len(index.Entries)
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				widget.NewLabel("name"),
+				layout.NewSpacer(),
+				widget.NewButton(i18n.T("action.show"), func() {}),
+				widget.NewButton(i18n.T("action.add_all"), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			locker.Lock()
+			if i >= len(index.Entries) {
+				locker.Unlock()
+				return
+			}
+			entry, kind := index.Entries[i], index.Kind
+			locker.Unlock()
+			fields := o.(*fyne.Container).Objects
+			fields[0].(*widget.Label).SetText(entry.Artist)
+			fields[2].(*widget.Button).OnTapped = func() {
+				mp.showSearch()
+				mp.browse(entry, kind)
+			}
+			fields[3].(*widget.Button).OnTapped = func() { go mp.add(entry, kind) }
+		})
+
+	// Selection only scrolls to the letter, so the same letter can be selected again
+	list.OnSelected = func(id widget.ListItemID) {
+		list.Unselect(id)
+	}
+
+	letters := container.NewHBox()
+	for _, letter := range music.Letters() {
+		target := letter
+		button := widget.NewButton(letter, func() {
+			locker.Lock()
+			position, exist := index.Position(target)
+			locker.Unlock()
+			if exist {
+				list.Select(position)
+			}
+		})
+		button.Importance = widget.LowImportance
+		letters.Add(button)
+	}
+	// Letters without names are disabled
+	showIndex := func(selected music.Index) {
+		locker.Lock()
+		index = selected
+		locker.Unlock()
+		for _, object := range letters.Objects {
+			button := object.(*widget.Button)
+			if _, exist := selected.Position(button.Text); exist {
+				button.Enable()
+			} else {
+				button.Disable()
+			}
+		}
+		list.Refresh()
+	}
+
+	kinds := []string{i18n.T("index.artists"), i18n.T("index.albums")}
+	kind := widget.NewRadioGroup(kinds, func(selected string) {
+		if selected == kinds[1] {
+			showIndex(mp.Wrapper().AlbumIndex())
+		} else {
+			showIndex(mp.Wrapper().ArtistIndex())
+		}
+	})
+	kind.Horizontal = true
+	kind.SetSelected(kinds[0])
+
+	top := container.NewVBox(kind, container.NewHScroll(letters))
+	win.SetContent(fyne.NewContainerWithLayout(layout.NewBorderLayout(top, nil, nil, nil), top, list))
+	win.Resize(fyne.Size{Width: 700, Height: 600})
+	win.Show()
+}
@@ -0,0 +1,51 @@
+package panel
+
+import (
+	"errors"
+	"fyne.io/fyne/dialog"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+)
+
+// Undo or redo last queue edit, a queue changed on player is never touched
+func (mp *MusicPanel) replay(run func() (string, error)) {
+	go func() {
+		name, err := run()
+		switch {
+		case errors.Is(err, music.ErrNothingToUndo), errors.Is(err, music.ErrNothingToRedo):
+			return
+		case errors.Is(err, music.ErrDiverged):
+			dialog.ShowInformation(i18n.T("journal.error"), i18n.T("journal.diverged", i18n.T("journal."+name)), mp.win)
+		case errors.Is(err, music.ErrPositionLost):
+			dialog.ShowInformation(i18n.T("journal.undone"), i18n.T("journal.lost"), mp.win)
+		case err != nil:
+			mp.reportError(i18n.T("journal.error"), err, func() { mp.replay(run) })
+		}
+		mp.updateChanel <- struct{}{}
+	}()
+}
+
+func (mp *MusicPanel) undo() {
+	mp.replay(mp.Wrapper().Undo)
+}
+
+func (mp *MusicPanel) redo() {
+	mp.replay(mp.Wrapper().Redo)
+}
+
+// Clear queue after confirmation, it can be undone
+func (mp *MusicPanel) clearQueue() {
+	dialog.ShowConfirm(i18n.T("queue.clear.title"), i18n.T("queue.clear.message"), func(confirm bool) {
+		if !confirm {
+			return
+		}
+		go mp.clear()
+	}, mp.win)
+}
+
+func (mp *MusicPanel) clear() {
+	if err := mp.Wrapper().Clear(); err != nil {
+		mp.reportError(i18n.T("queue.clear.error"), err, func() { go mp.clear() })
+	}
+	mp.updateChanel <- struct{}{}
+}
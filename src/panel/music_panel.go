@@ -6,105 +6,540 @@ import (
 	"fyne.io/fyne"
 	"fyne.io/fyne/canvas"
 	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
 	"fyne.io/fyne/layout"
 	"fyne.io/fyne/theme"
 	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/i18n"
 	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type MusicPanel struct {
-	musicWrapper music.MusicWrapper
+	// Asks a refresh of queue. Kept for the life of panel, routines of a previous profile can still send on it
 	updateChanel chan struct{}
 	searchPanel  fyne.Window
+	// Error met when loading artists and albums, displayed when main panel is created
+	indexErr error
+	app      fyne.App
+	win      fyne.Window
+	// Closed when profile is changed to stop refresh routines, guarded by controlLocker
+	stop chan struct{}
+	// All players of profile, guarded by controlLocker
+	rooms music.Rooms
+	// Serializes profile switches, they connect in background
+	switchLocker sync.Mutex
+	// Wrapper and name of the controlled room, changed from UI while refresh routines read them
+	controlLocker sync.RWMutex
+	musicWrapper  music.MusicWrapper
+	currentRoom   string
+	// Shared with settings window, which replaces it while refresh routines read it
+	confLocker sync.RWMutex
+	conf       *config.Config
+	confPath   string
+	bindings   keyBindings
+	// Player state is unknown, keep the last one asked to toggle pause. MPRIS reads and changes it too
+	paused atomic.Bool
+	// Called after a pause asked from panel, set before panel is created
+	onPauseChanged func()
+	progress       *music.Progress
+	watcher        *music.TrackWatcher
+	history        *music.History
+	ratings        *music.Ratings
+	playlists      *music.Playlists
+	// Number of commands waiting for player, hidden when there is none
+	pendingLabel *widget.Label
+	statusBadge  *statusBadge
+	// Radio feeding the queue, nil when stopped
+	radio       *music.Radio
+	radioLocker sync.Mutex
+	searchInput *shortcutEntry
+	// Number of tracks last added, shown in search window
+	searchStatus *widget.Label
+	// Open page of an artist or an album in search window
+	browse func(music.Music, music.Kind)
+	// Theme chosen by fyne from system settings, restored when configured theme is system
+	systemTheme fyne.Theme
 }
 
-func NewMusicPanel(urlServer, urlPlayer string, app fyne.App) MusicPanel {
-	server := music.NewMusicServerWrapper(urlServer)
-	player := music.NewMusicPlayerWrapper(urlPlayer)
-	mp := MusicPanel{
-		musicWrapper: music.NewMusicWrapper(server, player),
+func NewMusicPanel(conf config.Config, confPath string, app fyne.App) (*MusicPanel, error) {
+	mp := &MusicPanel{
 		updateChanel: make(chan struct{}, 10),
+		progress:     &music.Progress{},
+		app:          app,
+		systemTheme:  app.Settings().Theme(),
+		conf:         &conf,
+		confPath:     confPath,
 	}
-	mp.searchPanel = mp.createSearchMusic(app)
-	return mp
+	mp.applyTheme(conf.Theme)
+	i18n.SetLanguage(conf.Language)
+	historyPath, err := config.HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	mp.history = music.NewHistory(historyPath)
+	ratingsPath, err := config.RatingsPath()
+	if err != nil {
+		return nil, err
+	}
+	if mp.ratings, err = music.LoadRatings(ratingsPath); err != nil {
+		// Store stays usable, next save replaces the broken file
+		slog.Warn("ratings unreadable", "path", ratingsPath, "err", err)
+	}
+	playlistsPath, err := config.PlaylistsPath()
+	if err != nil {
+		return nil, err
+	}
+	if mp.playlists, err = music.LoadPlaylists(playlistsPath); err != nil {
+		slog.Warn("playlists unreadable", "path", playlistsPath, "err", err)
+	}
+	return mp, mp.connect()
+}
+
+// Create wrappers and search window for current profile
+func (mp *MusicPanel) connect() error {
+	conf := mp.settings()
+	profile := conf.Current()
+	serverClient, playerClient, err := profile.Clients(conf.Timeout)
+	if err != nil {
+		return err
+	}
+	server, indexErr := music.NewMusicServerWrapperWithClient(profile.ServerURL, serverClient)
+	player := music.NewMusicPlayerWrapperWithClient(profile.PlayerURL, playerClient)
+	rooms, err := createRooms(profile, player, conf.Timeout)
+	if err != nil {
+		return err
+	}
+	mp.setRadio(nil)
+	mp.controlLocker.Lock()
+	mp.musicWrapper = music.NewMusicWrapper(server, player).WithRatings(mp.ratings)
+	mp.currentRoom = mainRoom
+	mp.rooms = rooms
+	mp.stop = make(chan struct{})
+	mp.controlLocker.Unlock()
+	mp.indexErr = indexErr
+	bindings, err := conf.Bindings()
+	if err != nil {
+		slog.Warn("bad shortcuts, default ones are used", "err", err)
+	}
+	mp.bindings = bindings
+	mp.searchPanel = mp.createSearchMusic(mp.app)
+	return nil
 }
 
-func (mp MusicPanel) CreateMainPanel(win fyne.Window) {
-	mp.musicWrapper.SearchArtist("goldm")
+// Wrapper return wrapper of the controlled room, it changes with profile and room
+func (mp *MusicPanel) Wrapper() music.MusicWrapper {
+	mp.controlLocker.RLock()
+	defer mp.controlLocker.RUnlock()
+	return mp.musicWrapper
+}
 
-	musics, _ := mp.musicWrapper.GetPlaylist()
-	list := widget.NewList(
+// Copy of configuration, safe to read from any routine
+func (mp *MusicPanel) settings() config.Config {
+	mp.confLocker.RLock()
+	defer mp.confLocker.RUnlock()
+	conf := *mp.conf
+	conf.Profiles = append([]config.Profile{}, conf.Profiles...)
+	return conf
+}
+
+// Change configuration and save it
+func (mp *MusicPanel) updateConf(change func(conf *config.Config)) error {
+	mp.confLocker.Lock()
+	defer mp.confLocker.Unlock()
+	change(mp.conf)
+	return mp.conf.Save(mp.confPath)
+}
+
+// Name of the controlled room
+func (mp *MusicPanel) room() string {
+	mp.controlLocker.RLock()
+	defer mp.controlLocker.RUnlock()
+	return mp.currentRoom
+}
+
+// Rooms of current profile
+func (mp *MusicPanel) allRooms() music.Rooms {
+	mp.controlLocker.RLock()
+	defer mp.controlLocker.RUnlock()
+	return mp.rooms
+}
+
+// Channel closed when current profile is left
+func (mp *MusicPanel) stopped() chan struct{} {
+	mp.controlLocker.RLock()
+	defer mp.controlLocker.RUnlock()
+	return mp.stop
+}
+
+func (mp *MusicPanel) disconnect() {
+	mp.controlLocker.Lock()
+	close(mp.stop)
+	mp.controlLocker.Unlock()
+	mp.searchPanel.Close()
+}
+
+// SwitchProfile stop everything linked to current profile and rebuild panel with the new one.
+// Connecting loads artists and albums from server, it's done in background
+func (mp *MusicPanel) SwitchProfile(name string) {
+	go func() {
+		mp.switchLocker.Lock()
+		defer mp.switchLocker.Unlock()
+		if err := mp.updateConf(func(conf *config.Config) { conf.CurrentProfile = name }); err != nil {
+			mp.reportError(i18n.T("error.save"), err, nil)
+		}
+		mp.disconnect()
+		if err := mp.connect(); err != nil {
+			dialog.ShowError(err, mp.win)
+			return
+		}
+		mp.CreateMainPanel(mp.win)
+	}()
+}
+
+func (mp *MusicPanel) createMainMenu() *fyne.MainMenu {
+	conf := mp.settings()
+	current := conf.Current().Name
+	items := make([]*fyne.MenuItem, 0, len(conf.Profiles)+2)
+	for _, name := range conf.ProfileNames() {
+		profileName := name
+		label := name
+		if name == current {
+			label = "* " + name
+		}
+		items = append(items, fyne.NewMenuItem(label, func() { mp.SwitchProfile(profileName) }))
+	}
+	items = append(items, fyne.NewMenuItemSeparator(), fyne.NewMenuItem(i18n.T("settings.title"), mp.ShowSettings))
+
+	roomItems := []*fyne.MenuItem{fyne.NewMenuItem(i18n.T("rooms.all"), mp.ShowRooms), fyne.NewMenuItemSeparator()}
+	for _, room := range mp.allRooms() {
+		selectedRoom := room
+		roomItems = append(roomItems, fyne.NewMenuItem(roomLabel(room.Name), func() { mp.controlRoom(selectedRoom) }))
+	}
+	views := fyne.NewMenu(i18n.T("menu.views"), fyne.NewMenuItem(i18n.T("history.title"), mp.ShowHistory), fyne.NewMenuItem(i18n.T("stats.title"), mp.ShowStats),
+		fyne.NewMenuItem(i18n.T("favorites.title"), mp.ShowFavorites), fyne.NewMenuItem(i18n.T("playlists.title"), mp.ShowPlaylists), fyne.NewMenuItem(i18n.T("radio.title"), mp.ShowRadio), fyne.NewMenuItem(i18n.T("index.title"), mp.ShowIndex))
+	return fyne.NewMainMenu(fyne.NewMenu(i18n.T("menu.profiles"), items...), fyne.NewMenu(i18n.T("rooms.title"), roomItems...), views)
+}
+
+func (mp *MusicPanel) CreateMainPanel(win fyne.Window) {
+	mp.win = win
+	stop := mp.stopped()
+	updates := mp.updateChanel
+	mp.Wrapper().SearchArtist("goldm")
+
+	if mp.indexErr != nil {
+		mp.showError(win, mp.indexErr, func() {
+			if err := mp.Wrapper().ReloadIndex(); err != nil {
+				mp.showError(win, err, nil)
+			}
+		})
+	}
+
+	queue, err := mp.Wrapper().ReadQueue()
+	if err != nil {
+		mp.showError(win, err, func() { mp.updateChanel <- struct{}{} })
+	}
+	// Position of playing track, shown in bold
+	locker := sync.Mutex{}
+	playing := -1
+	var list *widget.List
+	var updateBar func()
+	selection := newQueueSelection(func() {
+		list.Refresh()
+		updateBar()
+	})
+	list = widget.NewList(
 		func() int {
-			return len(musics)
+			return len(queue.Musics)
 		},
 		func() fyne.CanvasObject {
 			del := createIcon(theme.DeleteIcon())
 			play := createIcon(theme.MediaPlayIcon())
 
 			return container.NewHBox(
+				widget.NewCheck("", func(bool) {}),
 				widget.NewLabel("template"),
 				layout.NewSpacer(),
 				container.NewPadded(widget.NewButton("", func() {}), play),
-				container.NewPadded(widget.NewButton("", func() {}), del))
+				container.NewPadded(widget.NewButton("", func() {}), del),
+				createRatingControls())
 		},
 		func(i widget.ListItemID, o fyne.CanvasObject) {
-			if i >= len(musics) {
+			displayed := queue
+			if i >= len(displayed.Musics) {
 				return
 			}
-			o.(*fyne.Container).Objects[0].(*widget.Label).SetText(fmt.Sprintf("%d - %s - %s", i+1, musics[i].Title, musics[i].Artist))
-			o.(*fyne.Container).Objects[2].(*fyne.Container).Objects[0].(*widget.Button).OnTapped = func() {
-				mp.musicWrapper.Play(i)
+			fields := o.(*fyne.Container).Objects
+			check := fields[0].(*widget.Check)
+			// Check is set before its callback, to not toggle row again
+			check.OnChanged = nil
+			check.SetChecked(selection.isSelected(i))
+			check.OnChanged = func(bool) { selection.toggle(i) }
+			label := fields[1].(*widget.Label)
+			label.SetText(fmt.Sprintf("%d - %s - %s", i+1, displayed.Musics[i].Title, displayed.Musics[i].Artist))
+			locker.Lock()
+			label.TextStyle = fyne.TextStyle{Bold: i == playing}
+			locker.Unlock()
+			label.Refresh()
+			fields[3].(*fyne.Container).Objects[0].(*widget.Button).OnTapped = func() {
+				mp.Wrapper().Play(i)
 			}
-			o.(*fyne.Container).Objects[3].(*fyne.Container).Objects[0].(*widget.Button).OnTapped = func() {
-				if err := mp.musicWrapper.Delete(i + 1); err != nil {
-					fmt.Println("ERROR", err)
-				} else {
-					mp.updateChanel <- struct{}{}
-				}
+			fields[4].(*fyne.Container).Objects[0].(*widget.Button).OnTapped = func() {
+				mp.remove(displayed.Entry(i))
 			}
+			mp.showRating(fields[5].(*fyne.Container), displayed.Musics[i])
 		})
+	// Selection of list is only used to get clicks, selected rows are checked
+	list.OnSelected = func(id widget.ListItemID) {
+		list.Unselect(id)
+		selection.click(id)
+	}
+	selection.trackModifiers(win.Canvas())
+	selectionBar, update := mp.createSelectionBar(selection, func() music.Queue {
+		locker.Lock()
+		defer locker.Unlock()
+		return queue
+	})
+	updateBar = update
 	go func() {
 		// Update current position
-		timer := time.NewTicker(10 * time.Second)
+		timer := time.NewTicker(time.Duration(mp.settings().PollInterval))
+		defer timer.Stop()
 		for {
-			<-timer.C
-			if pos, err := mp.musicWrapper.Current(); err == nil && pos < list.Length() {
-				list.Select(pos)
+			select {
+			case <-stop:
+				return
+			case <-timer.C:
+				if pos, err := mp.Wrapper().Current(); err == nil {
+					mp.replayPending()
+					locker.Lock()
+					changed := pos != playing
+					playing = pos
+					locker.Unlock()
+					if changed {
+						list.Refresh()
+					}
+				}
 			}
 		}
 	}()
 
-	button := widget.NewButton("Ajouter", func() {
-		mp.searchPanel.Show()
-	})
+	watcher := music.NewTrackWatcher()
+	mp.watcher = watcher
+	mp.notifyOnChange(watcher, stop)
+	mp.recordHistory(watcher, stop)
+	mp.autoContinue(watcher)
+	mp.playRadio(watcher)
+
+	actions := mp.playerActions()
+	actions[config.ActionSearch] = mp.showSearch
+	actions[config.ActionRemove] = func() {
+		locker.Lock()
+		entries := selection.entries(queue)
+		locker.Unlock()
+		if len(entries) > 0 {
+			go func() {
+				mp.reportQueueError(mp.send(music.NewDeleteCommand(entries...)), actions[config.ActionRemove])
+				mp.updateChanel <- struct{}{}
+			}()
+		}
+	}
+	mp.bindings.bind(win.Canvas(), actions)
+
+	button := widget.NewButton(i18n.T("action.add"), mp.showSearch)
 	widget.NewToolbarAction(theme.MediaPlayIcon(), func() {})
 
-	toolbar := mp.createMusicToolbar()
-	border := layout.NewBorderLayout(toolbar, button, nil, nil)
-	panel := fyne.NewContainerWithLayout(border, toolbar, list, button)
+	header := container.NewVBox(mp.createMusicToolbar(), mp.createNowPlaying(watcher, stop))
+	mp.monitorHealth(stop)
+	mp.pendingLabel = widget.NewLabel("")
+	mp.showPending()
+	footer := container.NewHBox(button, mp.pendingLabel, layout.NewSpacer(), selectionBar)
+	border := layout.NewBorderLayout(header, footer, nil, nil)
+	panel := fyne.NewContainerWithLayout(border, header, list, footer)
+	// Listeners are all registered, watch can start
+	go watcher.Watch(time.Duration(mp.settings().PollInterval), stop, func() (music.NowPlaying, error) {
+		return mp.Wrapper().CurrentTrack()
+	})
 
 	go func() {
 		for {
-			<-mp.updateChanel
-			musics, _ = mp.musicWrapper.GetPlaylist()
-			list.Refresh()
+			select {
+			case <-stop:
+				return
+			case <-updates:
+				read, _ := mp.Wrapper().ReadQueue()
+				locker.Lock()
+				queue = read
+				locker.Unlock()
+				selection.clear()
+			}
 		}
 	}()
 
+	win.SetMainMenu(mp.createMainMenu())
 	win.SetContent(panel)
 	panel.Show()
 }
 
-func (mp MusicPanel) createMusicToolbar() *widget.Toolbar {
+// Remove a track of queue, the queue is refreshed even on conflict to show its new state
+func (mp *MusicPanel) remove(entry music.QueueEntry) {
+	mp.reportQueueError(mp.send(music.NewDeleteCommand(entry)), func() { go mp.remove(entry) })
+	mp.updateChanel <- struct{}{}
+}
+
+// A conflict is shown to user as nothing was changed, other errors can be retried
+func (mp *MusicPanel) reportQueueError(err error, retry func()) {
+	if errors.Is(err, music.ErrConflict) {
+		slog.Info("queue changed on player", "err", err)
+		dialog.ShowInformation(i18n.T("queue.conflict.title"), i18n.T("queue.conflict.message"), mp.win)
+		return
+	}
+	if errors.Is(err, music.ErrMoveUnsupported) {
+		dialog.ShowInformation(i18n.T("error.queue"), i18n.T("queue.move.unsupported"), mp.win)
+		return
+	}
+	mp.reportError(i18n.T("error.queue"), err, retry)
+}
+
+// Log and show a failure of an action of user, retry runs the action again when it's given
+func (mp *MusicPanel) reportError(title string, err error, retry func()) {
+	if err == nil {
+		return
+	}
+	slog.Error("action failed", "action", title, "err", err)
+	if retry == nil {
+		dialog.ShowError(err, mp.win)
+		return
+	}
+	message := widget.NewLabel(err.Error())
+	message.Wrapping = fyne.TextWrapWord
+	dialog.ShowCustomConfirm(title, i18n.T("action.retry"), i18n.T("action.close"), message, func(again bool) {
+		if again {
+			retry()
+		}
+	}, mp.win)
+}
+
+// Add a song or all songs of an artist or album to the queue
+func (mp *MusicPanel) add(line music.Music, kind music.Kind) {
+	switch kind {
+	case music.SongKind:
+		mp.addAll([]music.Music{line})
+	case music.ArtistKind, music.AlbumKind:
+		// Songs are read from server now, only player can be unreachable
+		musics, err := mp.Wrapper().SongsOf(line, kind)
+		if err != nil {
+			mp.reportError(i18n.T("error.add"), err, func() { go mp.add(line, kind) })
+			return
+		}
+		mp.addAll(musics)
+	default:
+		slog.Error("music without kind can't be added", "id", line.Id, "kind", kind)
+	}
+}
+
+// Add musics to the queue, they wait in outbox while player is unreachable
+func (mp *MusicPanel) addAll(musics []music.Music) {
+	if len(musics) == 0 {
+		return
+	}
+	if err := mp.send(music.NewAddCommand(musics...)); err != nil {
+		mp.reportError(i18n.T("error.add"), err, func() { go mp.addAll(musics) })
+		return
+	}
+	mp.searchStatus.SetText(i18n.N("tracks.added", len(musics)))
+	mp.updateChanel <- struct{}{}
+}
+
+// Ask user to trust an unknown certificate before retrying, other errors are shown with a retry
+func (mp *MusicPanel) showError(win fyne.Window, err error, retry func()) {
+	var untrusted *music.UntrustedCertificateError
+	if !errors.As(err, &untrusted) {
+		mp.reportError(i18n.T("error.connection"), err, retry)
+		return
+	}
+	askTrust(win, untrusted, func() {
+		untrusted.Trust()
+		mp.rememberCertificate(untrusted)
+		if retry != nil {
+			retry()
+		}
+	})
+}
+
+// Ask user to trust an unknown certificate, trusted is called when user accepts it
+func askTrust(win fyne.Window, untrusted *music.UntrustedCertificateError, trusted func()) {
+	message := i18n.T("certificate.message", untrusted.Host, untrusted.Subject, untrusted.Fingerprint)
+	dialog.ShowConfirm(i18n.T("certificate.title"), message, func(trust bool) {
+		if trust {
+			trusted()
+		}
+	}, win)
+}
+
+// Save trusted certificate in configuration of matching endpoint
+func (mp *MusicPanel) rememberCertificate(untrusted *music.UntrustedCertificateError) {
+	err := mp.updateConf(func(conf *config.Config) {
+		profile := conf.Current()
+		if profile.Pin(untrusted.Endpoint, untrusted.Fingerprint) {
+			conf.SetProfile(profile)
+		}
+	})
+	if err != nil {
+		mp.reportError(i18n.T("error.save"), err, nil)
+	}
+}
+
+// Paused return the last state asked to player, from panel or from MPRIS
+func (mp *MusicPanel) Paused() bool {
+	return mp.paused.Load()
+}
+
+// MarkPaused remember a pause asked to player by another control, progress of current track is stopped too
+func (mp *MusicPanel) MarkPaused(paused bool) {
+	mp.paused.Store(paused)
+	if paused {
+		mp.progress.Pause(time.Now())
+	} else {
+		mp.progress.Resume(time.Now())
+	}
+}
 
-	pause := widget.NewToolbarAction(theme.MediaPlayIcon(), func() { mp.musicWrapper.UnPause() })
-	play := widget.NewToolbarAction(theme.MediaPauseIcon(), func() { mp.musicWrapper.Pause() })
-	previous := widget.NewToolbarAction(theme.MediaSkipPreviousIcon(), func() { mp.musicWrapper.Previous() })
-	next := widget.NewToolbarAction(theme.MediaSkipNextIcon(), func() { mp.musicWrapper.Next() })
-	vup := widget.NewToolbarAction(theme.VolumeUpIcon(), func() { mp.musicWrapper.VolumeUp() })
-	vdown := widget.NewToolbarAction(theme.VolumeDownIcon(), func() { mp.musicWrapper.VolumeDown() })
+// OnPauseChanged register a listener of pauses asked from panel, before it's created
+func (mp *MusicPanel) OnPauseChanged(listener func()) {
+	mp.onPauseChanged = listener
+}
+
+// Pause or resume player
+func (mp *MusicPanel) setPaused(paused bool) {
+	mp.MarkPaused(paused)
+	if paused {
+		mp.Wrapper().Pause()
+	} else {
+		mp.Wrapper().UnPause()
+	}
+	if mp.onPauseChanged != nil {
+		go mp.onPauseChanged()
+	}
+}
+
+func (mp *MusicPanel) createMusicToolbar() *widget.Toolbar {
+
+	pause := widget.NewToolbarAction(theme.MediaPlayIcon(), func() { mp.setPaused(false) })
+	play := widget.NewToolbarAction(theme.MediaPauseIcon(), func() { mp.setPaused(true) })
+	previous := widget.NewToolbarAction(theme.MediaSkipPreviousIcon(), mp.sendAction(music.NewSkipCommand(-1)))
+	next := widget.NewToolbarAction(theme.MediaSkipNextIcon(), mp.sendAction(music.NewSkipCommand(1)))
+	vup := widget.NewToolbarAction(theme.VolumeUpIcon(), mp.sendAction(music.NewVolumeCommand(1)))
+	vdown := widget.NewToolbarAction(theme.VolumeDownIcon(), mp.sendAction(music.NewVolumeCommand(-1)))
+	undo := widget.NewToolbarAction(theme.ContentUndoIcon(), mp.undo)
+	redo := widget.NewToolbarAction(theme.ContentRedoIcon(), mp.redo)
+	clear := widget.NewToolbarAction(theme.ContentClearIcon(), mp.clearQueue)
+	settings := widget.NewToolbarAction(theme.SettingsIcon(), mp.ShowSettings)
+	mp.statusBadge = newStatusBadge()
 	toolbar := widget.NewToolbar(
 		pause,
 		play,
@@ -114,18 +549,25 @@ func (mp MusicPanel) createMusicToolbar() *widget.Toolbar {
 		widget.NewToolbarSeparator(),
 		vdown,
 		vup,
+		widget.NewToolbarSeparator(),
+		undo,
+		redo,
+		clear,
+		widget.NewToolbarSpacer(),
+		mp.statusBadge,
+		settings,
 	)
 
 	return toolbar
 }
 
-func (mp MusicPanel) createSearchMusic(application fyne.App) fyne.Window {
+func (mp *MusicPanel) createSearchMusic(application fyne.App) fyne.Window {
 	locker := sync.Mutex{}
-	win := application.NewWindow("Ajouter musique")
+	win := application.NewWindow(i18n.T("search.title"))
 
-	chanArtist := make(chan music.Music, 1)
 	results := make([]music.Music, 0)
 	var kindSearch music.Kind
+	var browser *libraryBrowser
 
 	list := widget.NewList(
 		func() int {
@@ -149,98 +591,122 @@ func (mp MusicPanel) createSearchMusic(application fyne.App) fyne.Window {
 			case music.SongKind:
 				showSongLine(o, results[i], mp)
 			case music.ArtistKind, music.AlbumKind:
-				showArtistLine(o, results[i], chanArtist, mp, kindSearch)
+				line, kind := results[i], kindSearch
+				showArtistLine(o, line, mp, kind, func() { mp.openPage(browser, line, kind) })
 			}
 		})
 
-	input := widget.NewEntry()
-	input.PlaceHolder = "Rechercher..."
-
-	timeWaiter := time.NewTimer(2000)
-	timeWaiter.Stop()
-
-	updateMusics := func() {
+	// Result selected with mouse or arrows, added with enter
+	selected := -1
+	list.OnSelected = func(id widget.ListItemID) {
 		locker.Lock()
-		results, kindSearch = updateSearchResults(mp.musicWrapper, input.Text)
-		list.Refresh()
+		selected = id
+		locker.Unlock()
+	}
+	move := func(delta int) {
+		locker.Lock()
+		position := selected + delta
+		size := len(results)
+		locker.Unlock()
+		if position >= 0 && position < size {
+			list.Select(position)
+		}
+	}
+	addSelected := func() {
+		locker.Lock()
+		if selected < 0 || selected >= len(results) {
+			locker.Unlock()
+			return
+		}
+		line, kind := results[selected], kindSearch
 		locker.Unlock()
+		mp.add(line, kind)
 	}
 
-	updateMusicsByArtists := func(id music.Music) {
+	actions := mp.playerActions()
+	actions[config.ActionAdd] = addSelected
+	actions[config.ActionCloseSearch] = win.Hide
+	mp.bindings.bind(win.Canvas(), actions)
+	input := newShortcutEntry(mp.bindings, map[string]func(){
+		config.ActionAdd:         addSelected,
+		config.ActionCloseSearch: win.Hide,
+	}, move)
+	input.PlaceHolder = i18n.T("search.placeholder")
+	mp.searchInput = input
+	mp.searchStatus = widget.NewLabel("")
+
+	// New results, first one is selected
+	setResults := func(musics []music.Music, kind music.Kind) {
 		locker.Lock()
-		results, kindSearch = updateResults(mp.musicWrapper, id, kindSearch)
+		results, kindSearch = musics, kind
+		selected = -1
 		list.Refresh()
 		locker.Unlock()
+		if len(musics) > 0 {
+			list.Select(0)
+		}
 	}
 
-	// Detect search to launch, wait 300ms before launch to avoid many request
-	go func() {
-		for {
-			<-timeWaiter.C
-			updateMusics()
+	browser = newLibraryBrowser(list)
+	mp.browse = func(m music.Music, kind music.Kind) { mp.openPage(browser, m, kind) }
+
+	// New search goes back to results
+	var updateMusics func(value string)
+	updateMusics = func(value string) {
+		browser.popTo(0)
+		musics, kind, err := mp.Wrapper().HybridSearch(value)
+		if err != nil {
+			mp.reportError(i18n.T("error.search"), err, func() { go updateMusics(value) })
+			return
 		}
-	}()
+		setResults(musics, kind)
+	}
 
+	// Detect search to launch, wait 300ms before launch to avoid many request
+	debouncer := music.NewDebouncer(music.SearchDelay, music.SearchMinLength, updateMusics)
+	stop := mp.stopped()
 	go func() {
-		for {
-			updateMusicsByArtists(<-chanArtist)
-		}
+		<-stop
+		debouncer.Stop()
 	}()
 
-	input.OnChanged = func(value string) {
-		if len(value) >= 3 {
-			timeWaiter.Reset(time.Millisecond * 300)
-		}
-	}
+	input.OnChanged = debouncer.Input
 
-	border := layout.NewBorderLayout(input, nil, nil, nil)
+	top := container.NewVBox(input, browser.crumbs)
+	border := layout.NewBorderLayout(top, mp.searchStatus, nil, nil)
 
-	win.SetContent(fyne.NewContainerWithLayout(border, input, list))
-	win.Resize(fyne.Size{600, 600})
+	win.SetContent(fyne.NewContainerWithLayout(border, top, mp.searchStatus, browser.view))
+	win.Resize(fyne.Size{Width: 600, Height: 600})
 	win.Hide()
 	return win
 }
 
-func showArtistLine(o fyne.CanvasObject, line music.Music, c chan music.Music, mp MusicPanel, kind music.Kind) {
+// Line of an artist or an album, show opens its page
+func showArtistLine(o fyne.CanvasObject, line music.Music, mp *MusicPanel, kind music.Kind, show func()) {
 	fields := o.(*fyne.Container).Objects
 	fields[0].(*fyne.Container).Objects[0].(*widget.Label).SetText(line.Artist)
 	fields[0].(*fyne.Container).Objects[1].(*widget.Label).SetText("")
-	fields[2].(*widget.Button).SetText("Show")
-	fields[2].(*widget.Button).OnTapped = func() {
-		c <- line
-	}
-	fields[3].(*widget.Button).SetText("Add all")
+	fields[2].(*widget.Button).SetText(i18n.T("action.show"))
+	fields[2].(*widget.Button).OnTapped = show
+	fields[3].(*widget.Button).SetText(i18n.T("action.add_all"))
 	fields[3].(*widget.Button).Show()
 	fields[3].(*widget.Button).OnTapped = func() {
-		err := errors.New("no kind")
-		switch kind {
-		case music.ArtistKind:
-			err = mp.musicWrapper.AddAllArtist(line)
-		case music.AlbumKind:
-			err = mp.musicWrapper.AddAllAlbum(line)
-		}
-		if err != nil {
-			fmt.Println("ERROR", err)
-		} else {
-			mp.updateChanel <- struct{}{}
-		}
+		mp.add(line, kind)
 	}
+	fields[4].Hide()
 }
 
-func showSongLine(o fyne.CanvasObject, line music.Music, mp MusicPanel) {
+func showSongLine(o fyne.CanvasObject, line music.Music, mp *MusicPanel) {
 	fields := o.(*fyne.Container).Objects
 	fields[0].(*fyne.Container).Objects[0].(*widget.Label).SetText(line.Title)
 	fields[0].(*fyne.Container).Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s (%s)", line.Artist, line.Album))
-	fields[2].(*widget.Button).SetText("Add")
+	fields[2].(*widget.Button).SetText(i18n.T("action.add"))
 	fields[2].(*widget.Button).OnTapped = func() {
-		// Add
-		if err := mp.musicWrapper.Add(line); err != nil {
-			fmt.Println("ERROR", err)
-		} else {
-			mp.updateChanel <- struct{}{}
-		}
+		mp.add(line, music.SongKind)
 	}
 	fields[3].(*widget.Button).Hide()
+	fields[4].Show()
+	mp.showRating(fields[4].(*fyne.Container), line)
 }
 
 func createArtistLine() fyne.CanvasObject {
@@ -252,8 +718,9 @@ func createArtistLine() fyne.CanvasObject {
 			widget.NewLabel(""),
 		),
 		layout.NewSpacer(),
-		widget.NewButton("Show", func() {}),
-		widget.NewButton("Add all", func() {}))
+		widget.NewButton(i18n.T("action.show"), func() {}),
+		widget.NewButton(i18n.T("action.add_all"), func() {}),
+		createRatingControls())
 }
 
 func createSongLine() fyne.CanvasObject {
@@ -265,33 +732,10 @@ func createSongLine() fyne.CanvasObject {
 			widget.NewLabel("artist,album"),
 		),
 		layout.NewSpacer(),
-		widget.NewButton("Add", func() {}),
-		widget.NewButton("", func() {}))
-
-}
+		widget.NewButton(i18n.T("action.add"), func() {}),
+		widget.NewButton("", func() {}),
+		createRatingControls())
 
-func updateSearchResults(musicWrapper music.MusicWrapper, value string) ([]music.Music, music.Kind) {
-	musics, kind := musicWrapper.HybridSearch(value)
-	results := make([]music.Music, len(musics))
-	for i, m := range musics {
-		results[i] = m
-	}
-	return results, kind
-}
-
-func updateResults(musicWrapper music.MusicWrapper, m music.Music, kind music.Kind) ([]music.Music, music.Kind) {
-	var musics []*music.Music
-	switch kind {
-	case music.ArtistKind:
-		musics = musicWrapper.ShowArtist(m)
-	case music.AlbumKind:
-		musics = musicWrapper.ShowAlbum(m)
-	}
-	results := make([]music.Music, len(musics))
-	for i, m := range musics {
-		results[i] = *m
-	}
-	return results, music.SongKind
 }
 
 func createIcon(res fyne.Resource) *canvas.Image {
@@ -0,0 +1,42 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"github.com/jotitan/fyne_poc/src/music"
+	"strings"
+	"time"
+)
+
+// Minimal delay between two notifications, when skipping quickly only the last track is notified
+const notificationInterval = 5 * time.Second
+
+// Send a desktop notification each time the controlled player starts another track
+func (mp *MusicPanel) notifyOnChange(watcher *music.TrackWatcher, stop chan struct{}) {
+	throttler := music.NewThrottler(notificationInterval, mp.notifyTrack)
+	go func() {
+		<-stop
+		throttler.Stop()
+	}()
+	watcher.OnChange(func(previous, current music.NowPlaying) {
+		// Track playing when application starts or room changes is already visible
+		if current.Music.Id != "" && previous != (music.NowPlaying{}) && !mp.settings().DisableNotifications {
+			throttler.Input(current.Music)
+		}
+	})
+}
+
+// Notifications of fyne 1.4 are text only, no cover can be shown
+func (mp *MusicPanel) notifyTrack(m music.Music) {
+	details := make([]string, 0, 2)
+	for _, value := range []string{m.Artist, m.Album} {
+		if value != "" {
+			details = append(details, value)
+		}
+	}
+	title := m.Title
+	if room := mp.room(); room != mainRoom {
+		title = fmt.Sprintf("%s (%s)", title, roomLabel(room))
+	}
+	mp.app.SendNotification(fyne.NewNotification(title, strings.Join(details, " - ")))
+}
@@ -0,0 +1,113 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/canvas"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/theme"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"time"
+)
+
+// Refresh of progress bar, position is estimated locally so it doesn't request player
+const progressRefresh = 500 * time.Millisecond
+
+func formatDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// Create header with cover, title, links to artist and album and progress of current track
+func (mp *MusicPanel) createNowPlaying(watcher *music.TrackWatcher, stop chan struct{}) fyne.CanvasObject {
+	cover := canvas.NewImageFromResource(theme.FileAudioIcon())
+	cover.FillMode = canvas.ImageFillContain
+	cover.SetMinSize(fyne.Size{Width: 128, Height: 128})
+	title := widget.NewLabel(i18n.T("nowplaying.nothing"))
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	artist := widget.NewButton("", func() {})
+	artist.Importance = widget.LowImportance
+	album := widget.NewButton("", func() {})
+	album.Importance = widget.LowImportance
+	elapsed := widget.NewLabel("")
+	remaining := widget.NewLabel("")
+	bar := widget.NewProgressBar()
+	bar.TextFormatter = func() string { return "" }
+
+	watcher.OnChange(func(previous, current music.NowPlaying) {
+		m := current.Music
+		// Position of track playing when application starts or room changes is unknown
+		if previous == (music.NowPlaying{}) {
+			mp.progress.Unknown(m.Duration())
+		} else {
+			mp.progress.Start(m.Duration(), time.Now())
+		}
+		if m.Id == "" {
+			title.SetText(i18n.T("nowplaying.nothing"))
+		} else {
+			title.SetText(m.Title)
+		}
+		mp.setLink(artist, m, music.ArtistKind)
+		mp.setLink(album, m, music.AlbumKind)
+		var resource fyne.Resource = theme.FileAudioIcon()
+		if data, err := mp.Wrapper().Cover(m); err == nil {
+			resource = fyne.NewStaticResource(fmt.Sprintf("cover-%s", m.Id), data)
+		}
+		cover.Resource = resource
+		cover.Refresh()
+	})
+
+	go func() {
+		timer := time.NewTicker(progressRefresh)
+		defer timer.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-timer.C:
+				position, length, ok := mp.progress.Position(time.Now())
+				switch {
+				case !ok:
+					elapsed.SetText("")
+					remaining.SetText("")
+					bar.SetValue(0)
+				case length == 0:
+					elapsed.SetText(formatDuration(position))
+					remaining.SetText("")
+					bar.SetValue(0)
+				default:
+					elapsed.SetText(formatDuration(position))
+					remaining.SetText("-" + formatDuration(length-position))
+					bar.SetValue(float64(position) / float64(length))
+				}
+			}
+		}
+	}()
+
+	progress := fyne.NewContainerWithLayout(layout.NewBorderLayout(nil, nil, elapsed, remaining), elapsed, remaining, bar)
+	details := container.NewVBox(title, container.NewHBox(artist, album), progress)
+	return fyne.NewContainerWithLayout(layout.NewBorderLayout(nil, nil, cover, nil), cover, details)
+}
+
+// Link to page of artist or album of a music, disabled if it's not in index
+func (mp *MusicPanel) setLink(link *widget.Button, m music.Music, kind music.Kind) {
+	find, text := mp.Wrapper().ArtistOf, m.Artist
+	if kind == music.AlbumKind {
+		find, text = mp.Wrapper().AlbumOf, m.Album
+	}
+	link.SetText(text)
+	target, exist := find(m)
+	if !exist {
+		link.OnTapped = nil
+		link.Disable()
+		return
+	}
+	link.Enable()
+	link.OnTapped = func() {
+		mp.showSearch()
+		mp.browse(target, kind)
+	}
+}
@@ -0,0 +1,55 @@
+package panel
+
+import (
+	"errors"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+)
+
+// Send a command to player, it waits in outbox while player is unreachable.
+// Waiting commands refused before it are reported here, only the error of cmd is returned to be retried
+func (mp *MusicPanel) send(cmd music.Command) error {
+	err := mp.Wrapper().Send(cmd)
+	mp.showPending()
+	var replayErr *music.ReplayError
+	if errors.As(err, &replayErr) {
+		mp.reportQueueError(replayErr.Refused, nil)
+		return replayErr.Err
+	}
+	return err
+}
+
+// Send a command from a button, player refusing it can be retried
+func (mp *MusicPanel) sendAction(cmd music.Command) func() {
+	var action func()
+	action = func() {
+		go func() {
+			if err := mp.send(cmd); err != nil {
+				mp.reportError(i18n.T("error.refused"), err, action)
+			}
+		}()
+	}
+	return action
+}
+
+func (mp *MusicPanel) showPending() {
+	if mp.pendingLabel == nil {
+		return
+	}
+	if pending := mp.Wrapper().Pending(); pending > 0 {
+		mp.pendingLabel.SetText(i18n.N("outbox.pending", pending))
+		mp.pendingLabel.Show()
+	} else {
+		mp.pendingLabel.Hide()
+	}
+}
+
+// Player answers again, waiting commands are sent
+func (mp *MusicPanel) replayPending() {
+	if mp.Wrapper().Pending() == 0 {
+		return
+	}
+	mp.reportQueueError(mp.Wrapper().Replay(), nil)
+	mp.showPending()
+	mp.updateChanel <- struct{}{}
+}
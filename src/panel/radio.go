@@ -0,0 +1,134 @@
+package panel
+
+import (
+	"errors"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Radio adds songs by batch when fewer than radioMinRemaining songs remain in queue
+const (
+	radioMinRemaining = 2
+	radioBatchSize    = 5
+	radioNoRepeat     = 50
+)
+
+func (mp *MusicPanel) setRadio(radio *music.Radio) {
+	mp.radioLocker.Lock()
+	defer mp.radioLocker.Unlock()
+	mp.radio = radio
+}
+
+func (mp *MusicPanel) currentRadio() *music.Radio {
+	mp.radioLocker.Lock()
+	defer mp.radioLocker.Unlock()
+	return mp.radio
+}
+
+// Feed the queue of the controlled player while radio is started
+func (mp *MusicPanel) playRadio(watcher *music.TrackWatcher) {
+	watcher.OnChange(func(_, current music.NowPlaying) {
+		if radio := mp.currentRadio(); radio != nil {
+			mp.fillRadio(radio, current)
+		}
+	})
+}
+
+func (mp *MusicPanel) fillRadio(radio *music.Radio, current music.NowPlaying) {
+	added, err := radio.Fill(mp.Wrapper(), current, radioMinRemaining, radioBatchSize)
+	if err != nil {
+		slog.Warn("radio failed", "err", err)
+		return
+	}
+	if len(added) > 0 {
+		mp.updateChanel <- struct{}{}
+	}
+}
+
+// Comma separated values, empty ones are ignored
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Empty value is 0
+func parseOptionalInt(value string) (int, error) {
+	if value = strings.TrimSpace(value); value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// ShowRadio start or stop a radio playing random songs of catalog matching filters
+func (mp *MusicPanel) ShowRadio() {
+	win := mp.app.NewWindow(i18n.T("radio.title"))
+	artists := widget.NewEntry()
+	artists.SetPlaceHolder(i18n.T("radio.all"))
+	albums := widget.NewEntry()
+	albums.SetPlaceHolder(i18n.T("radio.all"))
+	excluded := widget.NewEntry()
+	fromYear := widget.NewEntry()
+	toYear := widget.NewEntry()
+	genres := widget.NewEntry()
+	seed := widget.NewEntry()
+	seed.SetPlaceHolder(i18n.T("radio.random"))
+	status := widget.NewLabel(i18n.T("radio.stopped"))
+	if mp.currentRadio() != nil {
+		status.SetText(i18n.T("radio.playing"))
+	}
+
+	start := widget.NewButton(i18n.T("radio.start"), func() {
+		from, errFrom := parseOptionalInt(fromYear.Text)
+		to, errTo := parseOptionalInt(toYear.Text)
+		seedValue, errSeed := parseOptionalInt(seed.Text)
+		if errFrom != nil || errTo != nil || errSeed != nil {
+			dialog.ShowError(errors.New(i18n.T("radio.bad_numbers")), win)
+			return
+		}
+		if seed.Text == "" {
+			seedValue = int(time.Now().UnixNano())
+		}
+		filter := music.RadioFilter{
+			Artists:         splitList(artists.Text),
+			Albums:          splitList(albums.Text),
+			ExcludedArtists: splitList(excluded.Text),
+			FromYear:        from,
+			ToYear:          to,
+			Genres:          splitList(genres.Text),
+		}
+		radio := music.NewRadio(mp.Wrapper(), filter, int64(seedValue), radioNoRepeat)
+		mp.setRadio(radio)
+		status.SetText(i18n.T("radio.playing_seed", seedValue))
+		// Queue is filled at once, then on each track change
+		go mp.fillRadio(radio, music.NowPlaying{})
+	})
+	stopRadio := widget.NewButton(i18n.T("radio.stop"), func() {
+		mp.setRadio(nil)
+		status.SetText(i18n.T("radio.stopped"))
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("radio.artists"), artists),
+		widget.NewFormItem(i18n.T("radio.albums"), albums),
+		widget.NewFormItem(i18n.T("radio.excluded"), excluded),
+		widget.NewFormItem(i18n.T("radio.years"), container.NewGridWithColumns(2, fromYear, toYear)),
+		widget.NewFormItem(i18n.T("radio.genres"), genres),
+		widget.NewFormItem(i18n.T("radio.seed"), seed),
+	)
+	win.SetContent(container.NewVBox(form, container.NewHBox(start, stopRadio, status)))
+	win.Resize(fyne.Size{Width: 500, Height: 350})
+	win.Show()
+}
@@ -0,0 +1,107 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+)
+
+// Theme has no star nor heart icons
+var (
+	starIcon       = fyne.NewStaticResource("star.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#f5b301" d="M12 17.27L18.18 21l-1.64-7.03L22 9.24l-7.19-.61L12 2 9.19 8.63 2 9.24l5.46 4.73L5.82 21z"/></svg>`))
+	emptyStarIcon  = fyne.NewStaticResource("star_empty.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#9e9e9e" d="M22 9.24l-7.19-.62L12 2 9.19 8.63 2 9.24l5.46 4.73L5.82 21 12 17.27 18.18 21l-1.63-7.03L22 9.24zM12 15.4l-3.76 2.27 1-4.28-3.32-2.88 4.38-.38L12 6.1l1.71 4.04 4.38.38-3.32 2.88 1 4.28L12 15.4z"/></svg>`))
+	heartIcon      = fyne.NewStaticResource("heart.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#e53935" d="M12 21.35l-1.45-1.32C5.4 15.36 2 12.28 2 8.5 2 5.42 4.42 3 7.5 3c1.74 0 3.41.81 4.5 2.09C13.09 3.81 14.76 3 16.5 3 19.58 3 22 5.42 22 8.5c0 3.78-3.4 6.86-8.55 11.54L12 21.35z"/></svg>`))
+	emptyHeartIcon = fyne.NewStaticResource("heart_empty.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#9e9e9e" d="M16.5 3c-1.74 0-3.41.81-4.5 2.09C10.91 3.81 9.24 3 7.5 3 4.42 3 2 5.42 2 8.5c0 3.78 3.4 6.86 8.55 11.54L12 21.35l1.45-1.32C18.6 15.36 22 12.28 22 8.5 22 5.42 19.58 3 16.5 3zm-4.4 15.55l-.1.1-.1-.1C7.14 14.24 4 11.39 4 8.5 4 6.5 5.5 5 7.5 5c1.54 0 3.04.99 3.57 2.36h1.87C13.46 5.99 14.96 5 16.5 5c2 0 3.5 1.5 3.5 3.5 0 2.89-3.14 5.74-7.9 10.05z"/></svg>`))
+)
+
+// Favorite button followed by a button by star
+func createRatingControls() *fyne.Container {
+	controls := container.NewHBox()
+	for i := 0; i <= music.MaxStars; i++ {
+		button := widget.NewButtonWithIcon("", emptyStarIcon, func() {})
+		button.Importance = widget.LowImportance
+		controls.Add(button)
+	}
+	return controls
+}
+
+// Display rating of music on controls, tapping the current stars again removes rating
+func (mp *MusicPanel) showRating(controls *fyne.Container, m music.Music) {
+	rating := mp.ratings.Get(m)
+	save := func(err error) {
+		if err != nil {
+			mp.reportError(i18n.T("error.rating"), err, nil)
+		}
+		mp.showRating(controls, m)
+	}
+	favorite := controls.Objects[0].(*widget.Button)
+	favorite.SetIcon(emptyHeartIcon)
+	if rating.Favorite {
+		favorite.SetIcon(heartIcon)
+	}
+	favorite.OnTapped = func() {
+		save(mp.ratings.SetFavorite(m, !rating.Favorite))
+	}
+	for stars := 1; stars <= music.MaxStars; stars++ {
+		button := controls.Objects[stars].(*widget.Button)
+		button.SetIcon(emptyStarIcon)
+		if stars <= rating.Stars {
+			button.SetIcon(starIcon)
+		}
+		value := stars
+		if value == rating.Stars {
+			value = 0
+		}
+		button.OnTapped = func() {
+			save(mp.ratings.Rate(m, value))
+		}
+	}
+}
+
+// ShowFavorites list favorite musics, to rate them or add them to the queue
+func (mp *MusicPanel) ShowFavorites() {
+	win := mp.app.NewWindow(i18n.T("favorites.title"))
+	favorites := mp.ratings.Favorites()
+	list := widget.NewList(
+		func() int {
+			return len(favorites)
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				widget.NewLabel("favorite"),
+				layout.NewSpacer(),
+				createRatingControls(),
+				widget.NewButton(i18n.T("action.add"), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(favorites) {
+				return
+			}
+			m := favorites[i].Music
+			fields := o.(*fyne.Container).Objects
+			fields[0].(*widget.Label).SetText(fmt.Sprintf("%s - %s (%s)", m.Title, m.Artist, m.Album))
+			mp.showRating(fields[2].(*fyne.Container), m)
+			fields[3].(*widget.Button).OnTapped = func() {
+				mp.add(m, music.SongKind)
+			}
+		})
+	refresh := widget.NewButton(i18n.T("action.refresh"), func() {
+		favorites = mp.ratings.Favorites()
+		list.Refresh()
+	})
+	addAll := widget.NewButton(i18n.T("action.add_all"), func() {
+		musics := make([]music.Music, len(favorites))
+		for i, favorite := range favorites {
+			musics[i] = favorite.Music
+		}
+		go mp.addAll(musics)
+	})
+	bar := container.NewHBox(refresh, layout.NewSpacer(), addAll)
+	win.SetContent(fyne.NewContainerWithLayout(layout.NewBorderLayout(bar, nil, nil, nil), bar, list))
+	win.Resize(fyne.Size{Width: 700, Height: 500})
+	win.Show()
+}
@@ -0,0 +1,177 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"sync"
+	"time"
+)
+
+// Name of room of main player, only its label is translated
+const mainRoom = "Principal"
+
+func roomLabel(name string) string {
+	if name == mainRoom {
+		return i18n.T("rooms.main")
+	}
+	return name
+}
+
+// Create rooms of profile, main player first
+func createRooms(profile config.Profile, player music.MusicPlayerWrapper, timeout config.Duration) (music.Rooms, error) {
+	rooms := music.Rooms{{Name: mainRoom, Player: player}}
+	for _, room := range profile.Rooms {
+		client, err := profile.RoomClient(room, timeout)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, music.Room{Name: room.Name, Player: music.NewMusicPlayerWrapperWithClient(room.URL, client)})
+	}
+	return rooms, nil
+}
+
+func formatNowPlaying(nowPlaying music.NowPlaying, err error) string {
+	switch {
+	case err != nil:
+		return i18n.T("rooms.unreachable")
+	case nowPlaying.Music.Id == "":
+		return i18n.N("rooms.idle", nowPlaying.Size)
+	default:
+		return fmt.Sprintf("%d/%d - %s - %s", nowPlaying.Index+1, nowPlaying.Size, nowPlaying.Music.Title, nowPlaying.Music.Artist)
+	}
+}
+
+// Select the room controlled by main panel
+func (mp *MusicPanel) controlRoom(room music.Room) {
+	mp.controlLocker.Lock()
+	mp.musicWrapper = mp.musicWrapper.WithPlayer(room.Player)
+	mp.currentRoom = room.Name
+	mp.controlLocker.Unlock()
+	mp.watcher.Reset()
+	mp.showPending()
+	mp.win.SetTitle(i18n.T("window.room", roomLabel(room.Name)))
+	mp.updateChanel <- struct{}{}
+}
+
+// ShowRooms list all players of profile with what they play, allow to control them alone or as a group
+func (mp *MusicPanel) ShowRooms() {
+	win := mp.app.NewWindow(i18n.T("rooms.title"))
+	rooms := mp.allRooms()
+	locker := sync.Mutex{}
+	states := make([]string, len(rooms))
+	selected := make(map[string]bool)
+
+	list := widget.NewList(
+		func() int {
+			return len(rooms)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("room")
+			name.TextStyle = fyne.TextStyle{Bold: true}
+			return container.NewHBox(
+				widget.NewCheck("", func(bool) {}),
+				container.NewVBox(name, widget.NewLabel("")),
+				layout.NewSpacer(),
+				widget.NewButton(i18n.T("rooms.control"), func() {}),
+				widget.NewButton(i18n.T("rooms.transfer"), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			room := rooms[i]
+			fields := o.(*fyne.Container).Objects
+			check := fields[0].(*widget.Check)
+			check.OnChanged = nil
+			check.SetChecked(selected[room.Name])
+			check.OnChanged = func(value bool) {
+				locker.Lock()
+				selected[room.Name] = value
+				locker.Unlock()
+			}
+			name := roomLabel(room.Name)
+			if room.Name == mp.room() {
+				name = i18n.T("rooms.controlled", name)
+			}
+			fields[1].(*fyne.Container).Objects[0].(*widget.Label).SetText(name)
+			locker.Lock()
+			fields[1].(*fyne.Container).Objects[1].(*widget.Label).SetText(states[i])
+			locker.Unlock()
+			fields[3].(*widget.Button).OnTapped = func() {
+				mp.controlRoom(room)
+			}
+			fields[4].(*widget.Button).OnTapped = func() {
+				if room.Name == mp.room() {
+					return
+				}
+				go func() {
+					wrapper := mp.Wrapper()
+					if err := wrapper.Transfer(wrapper.Player(), room.Player); err != nil {
+						dialog.ShowError(err, win)
+						return
+					}
+					mp.controlRoom(room)
+				}()
+			}
+		})
+
+	group := func(command func(music.Group) error) func() {
+		return func() {
+			locker.Lock()
+			names := make([]string, 0, len(selected))
+			for name, isSelected := range selected {
+				if isSelected {
+					names = append(names, name)
+				}
+			}
+			locker.Unlock()
+			go func() {
+				if err := command(rooms.Group(names...)); err != nil {
+					dialog.ShowError(err, win)
+				}
+			}()
+		}
+	}
+	groupBar := container.NewHBox(
+		widget.NewLabel(i18n.T("rooms.selection")),
+		widget.NewButton(i18n.T("rooms.pause"), group(music.Group.Pause)),
+		widget.NewButton(i18n.T("rooms.play"), group(music.Group.UnPause)),
+		widget.NewButton(i18n.T("rooms.volume_down"), group(music.Group.VolumeDown)),
+		widget.NewButton(i18n.T("rooms.volume_up"), group(music.Group.VolumeUp)),
+	)
+
+	refresh := func() {
+		for i, room := range rooms {
+			state := formatNowPlaying(mp.Wrapper().NowPlaying(room.Player))
+			locker.Lock()
+			states[i] = state
+			locker.Unlock()
+		}
+		list.Refresh()
+	}
+	closed := make(chan struct{})
+	win.SetOnClosed(func() { close(closed) })
+	stop := mp.stopped()
+	go func() {
+		timer := time.NewTicker(time.Duration(mp.settings().PollInterval))
+		defer timer.Stop()
+		for {
+			refresh()
+			select {
+			case <-stop:
+				return
+			case <-closed:
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+
+	win.SetContent(fyne.NewContainerWithLayout(layout.NewBorderLayout(nil, groupBar, nil, nil), groupBar, list))
+	win.Resize(fyne.Size{Width: 600, Height: 400})
+	win.Show()
+}
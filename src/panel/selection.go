@@ -0,0 +1,241 @@
+package panel
+
+import (
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/driver/desktop"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"sort"
+	"sync"
+)
+
+// queueSelection is the set of queue rows selected by user. A click selects one row, ctrl+click toggles a row and shift+click selects a range
+type queueSelection struct {
+	locker sync.Mutex
+	rows   map[int]bool
+	// Last clicked row, start of shift ranges
+	anchor int
+	shift  bool
+	ctrl   bool
+	// Called when selection changes
+	onChange func()
+}
+
+func newQueueSelection(onChange func()) *queueSelection {
+	return &queueSelection{rows: make(map[int]bool), anchor: -1, onChange: onChange}
+}
+
+// Follow shift and control keys of window, list doesn't give modifiers of clicks
+func (s *queueSelection) trackModifiers(canvas fyne.Canvas) {
+	desktopCanvas, ok := canvas.(desktop.Canvas)
+	if !ok {
+		return
+	}
+	setModifier := func(key *fyne.KeyEvent, pressed bool) {
+		s.locker.Lock()
+		defer s.locker.Unlock()
+		switch key.Name {
+		case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+			s.shift = pressed
+		case desktop.KeyControlLeft, desktop.KeyControlRight:
+			s.ctrl = pressed
+		}
+	}
+	desktopCanvas.SetOnKeyDown(func(key *fyne.KeyEvent) { setModifier(key, true) })
+	desktopCanvas.SetOnKeyUp(func(key *fyne.KeyEvent) { setModifier(key, false) })
+}
+
+func (s *queueSelection) click(row int) {
+	s.locker.Lock()
+	switch {
+	case s.shift && s.anchor >= 0:
+		from, to := s.anchor, row
+		if from > to {
+			from, to = to, from
+		}
+		if !s.ctrl {
+			s.rows = make(map[int]bool)
+		}
+		for i := from; i <= to; i++ {
+			s.rows[i] = true
+		}
+	case s.ctrl:
+		s.toggleRow(row)
+	default:
+		s.rows = map[int]bool{row: true}
+		s.anchor = row
+	}
+	s.locker.Unlock()
+	s.onChange()
+}
+
+// Toggle is used by row checks, like a ctrl+click
+func (s *queueSelection) toggle(row int) {
+	s.locker.Lock()
+	s.toggleRow(row)
+	s.locker.Unlock()
+	s.onChange()
+}
+
+func (s *queueSelection) toggleRow(row int) {
+	if s.rows[row] {
+		delete(s.rows, row)
+	} else {
+		s.rows[row] = true
+	}
+	s.anchor = row
+}
+
+func (s *queueSelection) isSelected(row int) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.rows[row]
+}
+
+// Selected rows sorted, rows start at 0
+func (s *queueSelection) selected() []int {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	rows := make([]int, 0, len(s.rows))
+	for row := range s.rows {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Entries of selected rows in queue displayed to user
+func (s *queueSelection) entries(queue music.Queue) []music.QueueEntry {
+	var entries []music.QueueEntry
+	for _, row := range s.selected() {
+		if row < len(queue.Musics) {
+			entries = append(entries, queue.Entry(row))
+		}
+	}
+	return entries
+}
+
+// Clear is called when queue changes, rows don't match the same tracks anymore
+func (s *queueSelection) clear() {
+	s.locker.Lock()
+	s.rows, s.anchor = make(map[int]bool), -1
+	s.locker.Unlock()
+	s.onChange()
+}
+
+// Buttons acting on selected rows of queue, musics return the current queue
+func (mp *MusicPanel) createSelectionBar(selection *queueSelection, displayed func() music.Queue) (fyne.CanvasObject, func()) {
+	count := widget.NewLabel("")
+	run := func(action func(queue music.Queue, entries []music.QueueEntry) error) func() {
+		return func() {
+			queue := displayed()
+			entries := selection.entries(queue)
+			if len(entries) == 0 {
+				return
+			}
+			go func() {
+				mp.reportQueueError(action(queue, entries), nil)
+				mp.updateChanel <- struct{}{}
+			}()
+		}
+	}
+	remove := widget.NewButton(i18n.T("selection.remove"), run(func(_ music.Queue, entries []music.QueueEntry) error {
+		return mp.send(music.NewDeleteCommand(entries...))
+	}))
+	top := widget.NewButton(i18n.T("selection.top"), run(func(_ music.Queue, entries []music.QueueEntry) error {
+		return mp.Wrapper().MoveToTop(entries)
+	}))
+	next := widget.NewButton(i18n.T("selection.next"), run(func(queue music.Queue, entries []music.QueueEntry) error {
+		current, err := mp.Wrapper().Current()
+		if err != nil {
+			return err
+		}
+		if current < 0 || current >= len(queue.Musics) {
+			return mp.Wrapper().MoveToTop(entries)
+		}
+		return mp.Wrapper().MoveAfter(entries, queue.Entry(current))
+	}))
+	save := widget.NewButton(i18n.T("action.save"), func() {
+		queue := displayed()
+		var selected []music.Music
+		for _, entry := range selection.entries(queue) {
+			selected = append(selected, queue.Musics[entry.Index-1])
+		}
+		if len(selected) > 0 {
+			mp.savePlaylist(selected)
+		}
+	})
+	buttons := []*widget.Button{remove, top, next, save}
+	update := func() {
+		size := len(selection.selected())
+		count.SetText(i18n.N("selection.count", size))
+		for _, button := range buttons {
+			// Move buttons are useless once player answered it can't move
+			if size == 0 || ((button == top || button == next) && !mp.Wrapper().CanMove()) {
+				button.Disable()
+			} else {
+				button.Enable()
+			}
+		}
+	}
+	update()
+	return container.NewHBox(count, remove, top, next, save), update
+}
+
+// Ask a name and save musics as a playlist
+func (mp *MusicPanel) savePlaylist(musics []music.Music) {
+	name := widget.NewEntry()
+	name.SetPlaceHolder(i18n.T("playlists.name"))
+	dialog.ShowCustomConfirm(i18n.T("selection.save"), i18n.T("action.save"), i18n.T("action.cancel"), name, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := mp.playlists.Save(name.Text, musics); err != nil {
+			dialog.ShowError(err, mp.win)
+		}
+	}, mp.win)
+}
+
+// ShowPlaylists list saved playlists to add them to the queue or delete them
+func (mp *MusicPanel) ShowPlaylists() {
+	win := mp.app.NewWindow(i18n.T("playlists.title"))
+	names := mp.playlists.Names()
+	var list *widget.List
+	list = widget.NewList(
+		func() int {
+			return len(names)
+		},
+		func() fyne.CanvasObject {
+			return container.NewHBox(
+				widget.NewLabel("playlist"),
+				layout.NewSpacer(),
+				widget.NewButton(i18n.T("action.add"), func() {}),
+				widget.NewButton(i18n.T("action.delete"), func() {}))
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(names) {
+				return
+			}
+			name := names[i]
+			musics := mp.playlists.Get(name)
+			fields := o.(*fyne.Container).Objects
+			fields[0].(*widget.Label).SetText(i18n.N("playlists.line", len(musics), name))
+			fields[2].(*widget.Button).OnTapped = func() {
+				go mp.addAll(musics)
+			}
+			fields[3].(*widget.Button).OnTapped = func() {
+				if err := mp.playlists.Delete(name); err != nil {
+					dialog.ShowError(err, win)
+				}
+				names = mp.playlists.Names()
+				list.Refresh()
+			}
+		})
+	win.SetContent(list)
+	win.Resize(fyne.Size{Width: 500, Height: 400})
+	win.Show()
+}
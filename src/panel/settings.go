@@ -0,0 +1,247 @@
+package panel
+
+import (
+	"errors"
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/theme"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"strconv"
+	"time"
+)
+
+var languages = []string{"system", "fr", "en"}
+var themes = []string{"system", "light", "dark"}
+
+// Keys of labels of config.DJStrategies
+var djStrategyKeys = []string{"dj.same_artist", "dj.same_album", "dj.recent_artists"}
+
+// Labels of keys in current language
+func translate(keys []string) []string {
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = i18n.T(key)
+	}
+	return labels
+}
+
+// Labels of values, prefix is the namespace of their keys
+func translateValues(prefix string, values []string) []string {
+	keys := make([]string, len(values))
+	for i, value := range values {
+		keys[i] = prefix + value
+	}
+	return translate(keys)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return 0
+}
+
+func (mp *MusicPanel) applyTheme(name string) {
+	switch name {
+	case "light":
+		mp.app.Settings().SetTheme(theme.LightTheme())
+	case "dark":
+		mp.app.Settings().SetTheme(theme.DarkTheme())
+	default:
+		// Only when another theme was applied, to let fyne follow system changes otherwise
+		if mp.app.Settings().Theme() != mp.systemTheme {
+			mp.app.Settings().SetTheme(mp.systemTheme)
+		}
+	}
+}
+
+func orSystem(value string) string {
+	if value == "" {
+		return "system"
+	}
+	return value
+}
+
+func fromSystem(value string) string {
+	if value == "system" {
+		return ""
+	}
+	return value
+}
+
+// Check that both endpoints of profile answer
+func probeEndpoints(profile config.Profile, timeout config.Duration) error {
+	serverClient, playerClient, err := profile.Clients(timeout)
+	if err != nil {
+		return err
+	}
+	if err := music.ProbeServer(profile.ServerURL, serverClient); err != nil {
+		return err
+	}
+	return music.ProbePlayer(profile.PlayerURL, playerClient)
+}
+
+// Search servers and players on local network, selecting one fill the matching entry
+func createDiscoveryPanel(server, player *widget.Entry) fyne.CanvasObject {
+	urls := make(map[string]string)
+	servers := widget.NewSelect([]string{}, func(name string) { server.SetText(urls[name]) })
+	servers.PlaceHolder = i18n.T("discovery.servers_found")
+	players := widget.NewSelect([]string{}, func(name string) { player.SetText(urls[name]) })
+	players.PlaceHolder = i18n.T("discovery.players_found")
+	status := widget.NewLabel("")
+
+	var search *widget.Button
+	search = widget.NewButton(i18n.T("discovery.search"), func() {
+		search.Disable()
+		status.SetText(i18n.T("discovery.searching"))
+		go func() {
+			defer search.Enable()
+			endpoints, err := music.NewDiscoverer().Discover()
+			if err != nil {
+				status.SetText(err.Error())
+				return
+			}
+			servers.Options, players.Options = []string{}, []string{}
+			for _, endpoint := range endpoints {
+				label := fmt.Sprintf("%s (%s)", endpoint.Name, endpoint.URL)
+				urls[label] = endpoint.URL
+				if endpoint.Kind == music.ServerEndpoint {
+					servers.Options = append(servers.Options, label)
+				} else {
+					players.Options = append(players.Options, label)
+				}
+			}
+			servers.Refresh()
+			players.Refresh()
+			status.SetText(i18n.T("discovery.found", i18n.N("discovery.servers", len(servers.Options)), i18n.N("discovery.players", len(players.Options))))
+		}()
+	})
+	return container.NewVBox(container.NewHBox(search, status), servers, players)
+}
+
+// ShowSettings edit current profile (a new one is created when name is changed) and global settings
+func (mp *MusicPanel) ShowSettings() {
+	win := mp.app.NewWindow(i18n.T("settings.title"))
+	saved := mp.settings()
+	current := saved.Current()
+
+	name := widget.NewEntry()
+	name.SetText(current.Name)
+	server := widget.NewEntry()
+	server.SetText(current.ServerURL)
+	player := widget.NewEntry()
+	player.SetText(current.PlayerURL)
+	username := widget.NewEntry()
+	username.SetText(current.Username)
+	password := widget.NewPasswordEntry()
+	password.SetText(current.Password)
+	timeout := widget.NewEntry()
+	timeout.SetText(time.Duration(saved.Timeout).String())
+	poll := widget.NewEntry()
+	poll.SetText(time.Duration(saved.PollInterval).String())
+	languageLabels, themeLabels := translateValues("language.", languages), translateValues("theme.", themes)
+	language := widget.NewSelect(languageLabels, func(string) {})
+	language.SetSelected(languageLabels[indexOf(languages, orSystem(saved.Language))])
+	themeSelect := widget.NewSelect(themeLabels, func(string) {})
+	themeSelect.SetSelected(themeLabels[indexOf(themes, orSystem(saved.Theme))])
+	notifications := widget.NewCheck(i18n.T("settings.track_change"), func(bool) {})
+	notifications.SetChecked(!saved.DisableNotifications)
+	autoDJ := widget.NewCheck(i18n.T("settings.fill_queue"), func(bool) {})
+	autoDJ.SetChecked(saved.AutoDJ.Enabled)
+	djStrategies := translate(djStrategyKeys)
+	strategy := widget.NewSelect(djStrategies, func(string) {})
+	strategy.SetSelected(djStrategies[saved.AutoDJ.DJStrategy()])
+	minRemaining := widget.NewEntry()
+	minRemaining.SetText(strconv.Itoa(saved.AutoDJ.MinRemaining))
+
+	discovered := createDiscoveryPanel(server, player)
+
+	form := widget.NewForm(
+		widget.NewFormItem(i18n.T("settings.profile"), name),
+		widget.NewFormItem(i18n.T("settings.server"), server),
+		widget.NewFormItem(i18n.T("settings.player"), player),
+		widget.NewFormItem(i18n.T("settings.username"), username),
+		widget.NewFormItem(i18n.T("settings.password"), password),
+		widget.NewFormItem(i18n.T("settings.timeout"), timeout),
+		widget.NewFormItem(i18n.T("settings.poll"), poll),
+		widget.NewFormItem(i18n.T("settings.language"), language),
+		widget.NewFormItem(i18n.T("settings.theme"), themeSelect),
+		widget.NewFormItem(i18n.T("settings.notifications"), notifications),
+		widget.NewFormItem(i18n.T("settings.auto_dj"), container.NewHBox(autoDJ, strategy)),
+		widget.NewFormItem(i18n.T("settings.min_remaining"), minRemaining),
+	)
+	form.SubmitText = i18n.T("action.save")
+	form.CancelText = i18n.T("action.cancel")
+	form.OnCancel = win.Close
+	form.OnSubmit = func() {
+		conf := mp.settings()
+		profile := current
+		profile.Name, profile.ServerURL, profile.PlayerURL = name.Text, server.Text, player.Text
+		profile.Username, profile.Password = username.Text, password.Text
+		conf.SetProfile(profile)
+		conf.CurrentProfile = profile.Name
+		conf.Language = fromSystem(languages[indexOf(languageLabels, language.Selected)])
+		conf.Theme = fromSystem(themes[indexOf(themeLabels, themeSelect.Selected)])
+		conf.DisableNotifications = !notifications.Checked
+		remaining, errRemaining := strconv.Atoi(minRemaining.Text)
+		if errRemaining != nil || remaining < 1 {
+			dialog.ShowError(errors.New(i18n.T("settings.bad_remaining")), win)
+			return
+		}
+		conf.AutoDJ = config.AutoDJ{Enabled: autoDJ.Checked, Strategy: config.DJStrategies[indexOf(djStrategies, strategy.Selected)], MinRemaining: remaining}
+		timeoutValue, errTimeout := time.ParseDuration(timeout.Text)
+		pollValue, errPoll := time.ParseDuration(poll.Text)
+		if errTimeout != nil || errPoll != nil {
+			dialog.ShowError(errors.New(i18n.T("settings.bad_duration")), win)
+			return
+		}
+		conf.Timeout, conf.PollInterval = config.Duration(timeoutValue), config.Duration(pollValue)
+		if err := conf.Validate(); err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		// Saved profile is checked, its unknown certificates are pinned in it when user trusts them
+		var save func()
+		save = func() {
+			go func() {
+				err := probeEndpoints(profile, conf.Timeout)
+				var untrusted *music.UntrustedCertificateError
+				if errors.As(err, &untrusted) {
+					askTrust(win, untrusted, func() {
+						if !profile.Pin(untrusted.Endpoint, untrusted.Fingerprint) {
+							dialog.ShowError(err, win)
+							return
+						}
+						conf.SetProfile(profile)
+						save()
+					})
+					return
+				}
+				if err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				if err := mp.updateConf(func(c *config.Config) { *c = conf }); err != nil {
+					dialog.ShowError(err, win)
+					return
+				}
+				mp.applyTheme(conf.Theme)
+				i18n.SetLanguage(conf.Language)
+				win.Close()
+				mp.SwitchProfile(profile.Name)
+			}()
+		}
+		save()
+	}
+
+	win.SetContent(container.NewVBox(discovered, form))
+	win.Resize(fyne.Size{Width: 500, Height: 500})
+	win.Show()
+}
@@ -0,0 +1,153 @@
+package panel
+
+import (
+	"fyne.io/fyne"
+	"fyne.io/fyne/driver/desktop"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/config"
+	"github.com/jotitan/fyne_poc/src/music"
+	"strings"
+)
+
+// Shortcuts of configuration, with a modifier they are fyne shortcuts, otherwise typed runes or keys
+type keyBindings map[string]config.KeyBinding
+
+func modifierOf(binding config.KeyBinding) desktop.Modifier {
+	var modifier desktop.Modifier
+	if binding.Ctrl {
+		modifier |= desktop.ControlModifier
+	}
+	if binding.Alt {
+		modifier |= desktop.AltModifier
+	}
+	if binding.Shift {
+		modifier |= desktop.ShiftModifier
+	}
+	return modifier
+}
+
+// Only control and alt make a shortcut, shift alone just changes typed character
+func isShortcut(binding config.KeyBinding) bool {
+	return binding.Ctrl || binding.Alt
+}
+
+func (kb keyBindings) matchRune(r rune, actions map[string]func()) func() {
+	for action, binding := range kb {
+		// Letters are typed lowercase, bindings are uppercase
+		if !isShortcut(binding) && binding.IsCharacter() && strings.ToUpper(string(r)) == binding.Key && actions[action] != nil {
+			return actions[action]
+		}
+	}
+	return nil
+}
+
+func (kb keyBindings) matchKey(key *fyne.KeyEvent, actions map[string]func()) func() {
+	name := string(key.Name)
+	// Keypad enter does the same as return
+	if key.Name == fyne.KeyEnter {
+		name = string(fyne.KeyReturn)
+	}
+	for action, binding := range kb {
+		if !isShortcut(binding) && !binding.IsCharacter() && strings.EqualFold(name, binding.Key) && actions[action] != nil {
+			return actions[action]
+		}
+	}
+	return nil
+}
+
+func (kb keyBindings) matchShortcut(shortcut fyne.Shortcut, actions map[string]func()) func() {
+	custom, ok := shortcut.(*desktop.CustomShortcut)
+	if !ok {
+		return nil
+	}
+	for action, binding := range kb {
+		if isShortcut(binding) && strings.EqualFold(string(custom.KeyName), binding.Key) && custom.Modifier == modifierOf(binding) && actions[action] != nil {
+			return actions[action]
+		}
+	}
+	return nil
+}
+
+// Bind actions on a canvas, they're used when no widget has focus
+func (kb keyBindings) bind(canvas fyne.Canvas, actions map[string]func()) {
+	canvas.SetOnTypedRune(func(r rune) {
+		if action := kb.matchRune(r, actions); action != nil {
+			action()
+		}
+	})
+	canvas.SetOnTypedKey(func(key *fyne.KeyEvent) {
+		if action := kb.matchKey(key, actions); action != nil {
+			action()
+		}
+	})
+	for name, binding := range kb {
+		if isShortcut(binding) && actions[name] != nil {
+			action := actions[name]
+			canvas.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyName(binding.Key), Modifier: modifierOf(binding)}, func(fyne.Shortcut) {
+				action()
+			})
+		}
+	}
+}
+
+// shortcutEntry is an entry which runs actions bound to named keys and shortcuts, characters are kept for typing
+type shortcutEntry struct {
+	widget.Entry
+	bindings keyBindings
+	actions  map[string]func()
+	// Called with up and down arrows to move in results
+	onMove func(delta int)
+}
+
+func newShortcutEntry(bindings keyBindings, actions map[string]func(), onMove func(int)) *shortcutEntry {
+	entry := &shortcutEntry{bindings: bindings, actions: actions, onMove: onMove}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+func (e *shortcutEntry) TypedKey(key *fyne.KeyEvent) {
+	if action := e.bindings.matchKey(key, e.actions); action != nil {
+		action()
+		return
+	}
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+func (e *shortcutEntry) TypedShortcut(shortcut fyne.Shortcut) {
+	if action := e.bindings.matchShortcut(shortcut, e.actions); action != nil {
+		action()
+		return
+	}
+	e.Entry.TypedShortcut(shortcut)
+}
+
+// Actions on player available from every window
+func (mp *MusicPanel) playerActions() map[string]func() {
+	return map[string]func(){
+		config.ActionPause:      mp.togglePause,
+		config.ActionNext:       mp.sendAction(music.NewSkipCommand(1)),
+		config.ActionPrevious:   mp.sendAction(music.NewSkipCommand(-1)),
+		config.ActionVolumeUp:   mp.sendAction(music.NewVolumeCommand(1)),
+		config.ActionVolumeDown: mp.sendAction(music.NewVolumeCommand(-1)),
+		config.ActionUndo:       mp.undo,
+		config.ActionRedo:       mp.redo,
+	}
+}
+
+func (mp *MusicPanel) togglePause() {
+	mp.setPaused(!mp.Paused())
+}
+
+// Show search window with focus in search field
+func (mp *MusicPanel) showSearch() {
+	mp.searchPanel.Show()
+	mp.searchPanel.RequestFocus()
+	mp.searchPanel.Canvas().Focus(mp.searchInput)
+}
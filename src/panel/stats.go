@@ -0,0 +1,144 @@
+package panel
+
+import (
+	"fmt"
+	"fyne.io/fyne"
+	"fyne.io/fyne/canvas"
+	"fyne.io/fyne/container"
+	"fyne.io/fyne/dialog"
+	"fyne.io/fyne/layout"
+	"fyne.io/fyne/theme"
+	"fyne.io/fyne/widget"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"image/color"
+	"io"
+	"time"
+)
+
+const statsTopSize = 10
+
+// Keys of labels in catalog, in order of music.Period
+var periodKeys = []string{"stats.week", "stats.month", "stats.year"}
+var weekDayKeys = []string{"day.monday", "day.tuesday", "day.wednesday", "day.thursday", "day.friday", "day.saturday", "day.sunday"}
+
+// ShowStats display top artists, albums and tracks and listening hours of a week, a month or a year
+func (mp *MusicPanel) ShowStats() {
+	win := mp.app.NewWindow(i18n.T("stats.title"))
+	period := music.WeekPeriod
+	date := time.Now()
+	var stats music.Stats
+
+	title := widget.NewLabel("")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	content := container.NewVBox()
+	refresh := func() {
+		from, to := period.Range(date)
+		entries, err := mp.history.Read(from, to)
+		if err != nil {
+			dialog.ShowError(err, win)
+			return
+		}
+		stats = music.ComputeStats(entries, from, to, statsTopSize)
+		dateFormat := i18n.T("date.format")
+		title.SetText(i18n.N("stats.summary", stats.Plays, from.Format(dateFormat), to.AddDate(0, 0, -1).Format(dateFormat), formatDuration(stats.Total)))
+		content.Objects = []fyne.CanvasObject{
+			container.NewGridWithColumns(3,
+				topList(i18n.T("stats.artists"), stats.TopArtists),
+				topList(i18n.T("stats.albums"), stats.TopAlbums),
+				topList(i18n.T("stats.tracks"), stats.TopTracks)),
+			heatmap(stats.Heatmap),
+		}
+		content.Refresh()
+	}
+	move := func(delta int) func() {
+		return func() {
+			switch period {
+			case music.WeekPeriod:
+				date = date.AddDate(0, 0, 7*delta)
+			case music.MonthPeriod:
+				// From first day of month, January 31 plus one month would be in March
+				date = date.AddDate(0, delta, 1-date.Day())
+			default:
+				date = date.AddDate(delta, 0, 0)
+			}
+			refresh()
+		}
+	}
+	periods := translate(periodKeys)
+	periodSelect := widget.NewSelect(periods, func(selected string) {
+		for i, name := range periods {
+			if name == selected {
+				period = music.Period(i)
+			}
+		}
+		refresh()
+	})
+	export := func(write func(music.Stats, io.Writer) error) func() {
+		return func() {
+			dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				if err = write(stats, writer); err == nil {
+					err = writer.Close()
+				}
+				if err != nil {
+					dialog.ShowError(err, win)
+				}
+			}, win)
+		}
+	}
+	bar := container.NewHBox(
+		widget.NewButton("<", move(-1)),
+		periodSelect,
+		widget.NewButton(">", move(1)),
+		layout.NewSpacer(),
+		widget.NewButton(i18n.T("stats.export_csv"), export(music.Stats.WriteCSV)),
+		widget.NewButton(i18n.T("stats.export_json"), export(music.Stats.WriteJSON)))
+	periodSelect.SetSelected(periods[period])
+
+	win.SetContent(container.NewVBox(bar, title, content))
+	win.Resize(fyne.Size{Width: 800, Height: 600})
+	win.Show()
+}
+
+func topList(name string, counts []music.Count) fyne.CanvasObject {
+	header := widget.NewLabel(name)
+	header.TextStyle = fyne.TextStyle{Bold: true}
+	lines := container.NewVBox(header)
+	for i, count := range counts {
+		lines.Add(widget.NewLabel(fmt.Sprintf("%d. %s (%d)", i+1, count.Name, count.Plays)))
+	}
+	return lines
+}
+
+// Grid of days and hours, darker when more listened
+func heatmap(values [7][24]time.Duration) fyne.CanvasObject {
+	var highest time.Duration
+	for _, hours := range values {
+		for _, value := range hours {
+			if value > highest {
+				highest = value
+			}
+		}
+	}
+	grid := container.NewGridWithColumns(25, widget.NewLabel(""))
+	for hour := 0; hour < 24; hour++ {
+		grid.Add(widget.NewLabel(fmt.Sprintf("%d", hour)))
+	}
+	r, g, b, _ := theme.PrimaryColor().RGBA()
+	for day, hours := range values {
+		grid.Add(widget.NewLabel(i18n.T(weekDayKeys[day])))
+		for _, value := range hours {
+			alpha := uint8(20)
+			if highest > 0 {
+				alpha += uint8(235 * value / highest)
+			}
+			cell := canvas.NewRectangle(color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: alpha})
+			cell.SetMinSize(fyne.Size{Width: 16, Height: 16})
+			grid.Add(cell)
+		}
+	}
+	return grid
+}
@@ -0,0 +1,70 @@
+package tui
+
+import "unicode/utf8"
+
+// Special keys, printable characters are sent as rune
+const (
+	keyRune = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+	keyBackspace
+	keyDelete
+	keyTab
+	keyEscape
+	keyCtrlA
+	keyCtrlC
+)
+
+type key struct {
+	kind  int
+	value rune
+}
+
+// Decode keys read from a raw terminal
+func decodeKeys(data []byte) []key {
+	var keys []key
+	for i := 0; i < len(data); {
+		switch b := data[i]; {
+		case b == 0x1b && i+2 < len(data) && data[i+1] == '[':
+			arrows := map[byte]int{'A': keyUp, 'B': keyDown, 'C': keyRight, 'D': keyLeft}
+			if kind, exist := arrows[data[i+2]]; exist {
+				keys = append(keys, key{kind: kind})
+				i += 3
+			} else if data[i+2] == '3' && i+3 < len(data) && data[i+3] == '~' {
+				keys = append(keys, key{kind: keyDelete})
+				i += 4
+			} else {
+				// Unknown sequence, ignore it
+				i += 3
+			}
+		case b == 0x1b:
+			keys = append(keys, key{kind: keyEscape})
+			i++
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{kind: keyEnter})
+			i++
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{kind: keyBackspace})
+			i++
+		case b == '\t':
+			keys = append(keys, key{kind: keyTab})
+			i++
+		case b == 0x01:
+			keys = append(keys, key{kind: keyCtrlA})
+			i++
+		case b == 0x03:
+			keys = append(keys, key{kind: keyCtrlC})
+			i++
+		case b < 0x20:
+			i++
+		default:
+			r, size := utf8.DecodeRune(data[i:])
+			keys = append(keys, key{kind: keyRune, value: r})
+			i += size
+		}
+	}
+	return keys
+}
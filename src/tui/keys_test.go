@@ -0,0 +1,16 @@
+package tui
+
+import "testing"
+
+func TestDecodeKeys(t *testing.T) {
+	keys := decodeKeys([]byte("a\x1b[Bé\r\x1b[3~\x1b\x7f"))
+	expected := []key{{keyRune, 'a'}, {keyDown, 0}, {keyRune, 'é'}, {keyEnter, 0}, {keyDelete, 0}, {keyEscape, 0}, {keyBackspace, 0}}
+	if len(keys) != len(expected) {
+		t.Fatal("bad keys", keys)
+	}
+	for i, k := range keys {
+		if k != expected[i] {
+			t.Error("expected", expected[i], "got", k)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"os"
+	"strings"
+)
+
+const (
+	reverse = "\x1b[7m"
+	bold    = "\x1b[1m"
+	reset   = "\x1b[0m"
+)
+
+// Cut or pad text to width
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) > width {
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-len(runes))
+}
+
+// Return first line to display so that cursor is visible
+func firstVisible(cursor, height, size int) int {
+	if size <= height || cursor < height/2 {
+		return 0
+	}
+	if cursor > size-height/2 {
+		return size - height
+	}
+	return cursor - height/2
+}
+
+// Lines of a pane : a bold header, then items, selected one in reverse video
+func paneLines(header string, items []string, cursor, marked int, focused bool, width, height int) []string {
+	lines := []string{bold + fit(header, width) + reset}
+	start := firstVisible(cursor, height-1, len(items))
+	for i := start; i < len(items) && len(lines) < height; i++ {
+		prefix := "  "
+		if i == marked {
+			prefix = "▶ "
+		}
+		line := fit(prefix+items[i], width)
+		if i == cursor && focused {
+			line = reverse + line + reset
+		} else if i == marked {
+			line = bold + line + reset
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < height {
+		lines = append(lines, strings.Repeat(" ", width))
+	}
+	return lines
+}
+
+func (a *App) draw(fd int) {
+	width, height, err := terminalSize(fd)
+	if err != nil || width < 20 || height < 5 {
+		width, height = 80, 24
+	}
+	a.locker.Lock()
+	defer a.locker.Unlock()
+
+	queueItems := make([]string, len(a.queue.Musics))
+	for i, m := range a.queue.Musics {
+		queueItems[i] = fmt.Sprintf("%d - %s", i+1, describe(m, music.SongKind))
+	}
+	searchItems := make([]string, len(a.results))
+	for i, m := range a.results {
+		searchItems[i] = describe(m, a.kind)
+	}
+	state := "▶"
+	if a.paused {
+		state = "⏸"
+	}
+	searchHeader := i18n.T("tui.search", a.query)
+	if a.focus == searchPane {
+		searchHeader += "_"
+	}
+	if len(a.history) > 0 {
+		searchHeader += "  " + i18n.T("tui.back")
+	}
+
+	leftWidth := width / 2
+	rightWidth := width - leftWidth - 1
+	paneHeight := height - 3
+	left := paneLines(i18n.T("tui.queue"), queueItems, a.queueCursor, a.current, a.focus == queuePane, leftWidth, paneHeight)
+	right := paneLines(searchHeader, searchItems, a.cursor, -1, a.focus == searchPane, rightWidth, paneHeight)
+
+	var screen strings.Builder
+	screen.WriteString("\x1b[H\x1b[2J")
+	screen.WriteString(reverse + fit(fmt.Sprintf(" %s %s", state, a.title), width) + reset + "\r\n")
+	for i := 0; i < paneHeight; i++ {
+		screen.WriteString(left[i] + "│" + right[i] + "\r\n")
+	}
+	screen.WriteString(fit(a.status, width) + "\r\n")
+	screen.WriteString(reverse + fit(a.helpLine(), width) + reset)
+	os.Stdout.WriteString(screen.String())
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package tui
+
+import "errors"
+
+var errUnsupported = errors.New("terminal mode is only supported on linux")
+
+func makeRaw(fd int) (func(), error) {
+	return nil, errUnsupported
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/jotitan/fyne_poc/src/i18n"
+	"github.com/jotitan/fyne_poc/src/music"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	queuePane = iota
+	searchPane
+)
+
+// Drill-down level of search results, to go back with escape
+type searchLevel struct {
+	results []music.Music
+	kind    music.Kind
+	cursor  int
+}
+
+// App is a terminal version of the music panel : a queue and a search pane
+type App struct {
+	locker  sync.Mutex
+	wrapper music.MusicWrapper
+	title   string
+	poll    time.Duration
+
+	queue       music.Queue
+	current     int
+	queueCursor int
+	paused      bool
+
+	focus   int
+	query   string
+	results []music.Music
+	kind    music.Kind
+	cursor  int
+	history []searchLevel
+	// Incremented each time results change, songs loaded for older results are dropped
+	version int
+
+	status string
+	redraw chan struct{}
+}
+
+func NewApp(wrapper music.MusicWrapper, title string, poll time.Duration) *App {
+	return &App{
+		wrapper: wrapper,
+		title:   title,
+		poll:    poll,
+		kind:    music.SongKind,
+		redraw:  make(chan struct{}, 1),
+	}
+}
+
+// Run take control of terminal until user quits
+func (a *App) Run() error {
+	fd := int(os.Stdin.Fd())
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	// Alternate screen and hidden cursor
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []byte)
+	go func() {
+		buffer := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buffer)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- append([]byte{}, buffer[:n]...)
+		}
+	}()
+
+	debouncer := music.NewDebouncer(music.SearchDelay, music.SearchMinLength, a.search)
+	defer debouncer.Stop()
+	timer := time.NewTicker(a.poll)
+	defer timer.Stop()
+
+	go a.refreshQueue()
+	for {
+		a.draw(fd)
+		select {
+		case data, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range decodeKeys(data) {
+				if quit := a.handleKey(k, debouncer); quit {
+					return nil
+				}
+			}
+		case <-timer.C:
+			go a.refreshQueue()
+		case <-a.redraw:
+		}
+	}
+}
+
+func (a *App) askRedraw() {
+	select {
+	case a.redraw <- struct{}{}:
+	default:
+	}
+}
+
+func (a *App) setStatus(err error, success string) {
+	a.locker.Lock()
+	if err != nil {
+		a.status = i18n.T("tui.error", err.Error())
+	} else {
+		a.status = success
+	}
+	a.locker.Unlock()
+	a.askRedraw()
+}
+
+func (a *App) refreshQueue() {
+	queue, err := a.wrapper.ReadQueue()
+	if err != nil {
+		a.setStatus(err, "")
+		return
+	}
+	current, _ := a.wrapper.Current()
+	a.locker.Lock()
+	a.queue, a.current = queue, current
+	if a.queueCursor >= len(queue.Musics) {
+		a.queueCursor = len(queue.Musics) - 1
+	}
+	if a.queueCursor < 0 {
+		a.queueCursor = 0
+	}
+	a.locker.Unlock()
+	a.askRedraw()
+}
+
+func (a *App) search(term string) {
+	results, kind, err := a.wrapper.HybridSearch(term)
+	if err != nil {
+		a.setStatus(err, "")
+		return
+	}
+	a.locker.Lock()
+	a.results, a.kind, a.cursor, a.history = results, kind, 0, nil
+	a.version++
+	a.locker.Unlock()
+	a.askRedraw()
+}
+
+// Launch a command on player in background, then refresh queue
+func (a *App) command(action func() error, success string) {
+	go func() {
+		err := action()
+		a.setStatus(err, success)
+		if err == nil {
+			a.refreshQueue()
+		}
+	}()
+}
+
+func (a *App) togglePause() {
+	a.paused = !a.paused
+	if a.paused {
+		a.command(a.wrapper.Pause, i18n.T("tui.paused"))
+	} else {
+		a.command(a.wrapper.UnPause, i18n.T("tui.playing"))
+	}
+}
+
+func moveCursor(cursor, delta, size int) int {
+	cursor += delta
+	if cursor >= size {
+		cursor = size - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
+
+// Return true if app must quit
+func (a *App) handleKey(k key, debouncer *music.Debouncer) bool {
+	a.locker.Lock()
+	defer a.locker.Unlock()
+	if k.kind == keyCtrlC {
+		return true
+	}
+	if k.kind == keyTab {
+		a.focus = (a.focus + 1) % 2
+		return false
+	}
+	if a.focus == queuePane {
+		return a.handleQueueKey(k)
+	}
+	a.handleSearchKey(k, debouncer)
+	return false
+}
+
+func (a *App) handleQueueKey(k key) bool {
+	switch k.kind {
+	case keyUp:
+		a.queueCursor = moveCursor(a.queueCursor, -1, len(a.queue.Musics))
+	case keyDown:
+		a.queueCursor = moveCursor(a.queueCursor, 1, len(a.queue.Musics))
+	case keyEnter:
+		index := a.queueCursor
+		a.command(func() error { return a.wrapper.Play(index) }, i18n.T("tui.playing"))
+	case keyDelete:
+		if a.queueCursor >= len(a.queue.Musics) {
+			break
+		}
+		entry := a.queue.Entry(a.queueCursor)
+		a.command(func() error { return a.wrapper.Delete(entry) }, i18n.T("tui.removed"))
+	case keyRune:
+		switch k.value {
+		case 'q':
+			return true
+		case ' ':
+			a.togglePause()
+		case 'n':
+			a.command(a.wrapper.Next, i18n.T("tui.next"))
+		case 'p':
+			a.command(a.wrapper.Previous, i18n.T("tui.previous"))
+		case '+':
+			a.command(a.wrapper.VolumeUp, i18n.T("tui.volume_up"))
+		case '-':
+			a.command(a.wrapper.VolumeDown, i18n.T("tui.volume_down"))
+		case 'k':
+			a.queueCursor = moveCursor(a.queueCursor, -1, len(a.queue.Musics))
+		case 'j':
+			a.queueCursor = moveCursor(a.queueCursor, 1, len(a.queue.Musics))
+		case '/':
+			a.focus = searchPane
+		}
+	}
+	return false
+}
+
+func (a *App) handleSearchKey(k key, debouncer *music.Debouncer) {
+	switch k.kind {
+	case keyUp:
+		a.cursor = moveCursor(a.cursor, -1, len(a.results))
+	case keyDown:
+		a.cursor = moveCursor(a.cursor, 1, len(a.results))
+	case keyEscape:
+		a.back()
+	case keyBackspace:
+		if len(a.query) > 0 {
+			runes := []rune(a.query)
+			a.query = string(runes[:len(runes)-1])
+			debouncer.Input(a.query)
+		}
+	case keyEnter:
+		a.open()
+	case keyCtrlA:
+		a.addAll()
+	case keyRune:
+		a.query += string(k.value)
+		debouncer.Input(a.query)
+	}
+}
+
+// Add selected song or show songs of selected artist or album. Songs are read in background, called with lock
+func (a *App) open() {
+	if a.cursor >= len(a.results) {
+		return
+	}
+	selected := a.results[a.cursor]
+	if a.kind == music.SongKind {
+		a.command(func() error { return a.wrapper.Add(selected) }, i18n.T("tui.added", selected.Title))
+		return
+	}
+	level, version := searchLevel{a.results, a.kind, a.cursor}, a.version
+	go func() {
+		songs, err := a.wrapper.SongsOf(selected, level.kind)
+		if err != nil {
+			a.setStatus(err, "")
+			return
+		}
+		a.locker.Lock()
+		if a.version == version {
+			a.history = append(a.history, level)
+			a.results, a.cursor, a.kind = songs, 0, music.SongKind
+			a.version++
+		}
+		a.locker.Unlock()
+		a.askRedraw()
+	}()
+}
+
+func (a *App) back() {
+	if len(a.history) == 0 {
+		a.focus = queuePane
+		return
+	}
+	previous := a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+	a.results, a.kind, a.cursor = previous.results, previous.kind, previous.cursor
+	a.version++
+}
+
+// Add all songs of selected artist or album, or all displayed songs
+func (a *App) addAll() {
+	if a.cursor >= len(a.results) {
+		return
+	}
+	selected := a.results[a.cursor]
+	switch a.kind {
+	case music.ArtistKind:
+		a.command(func() error { return a.wrapper.AddAllArtist(selected) }, i18n.T("tui.added", selected.Artist))
+	case music.AlbumKind:
+		a.command(func() error { return a.wrapper.AddAllAlbum(selected) }, i18n.T("tui.added", selected.Album))
+	default:
+		results := a.results
+		a.command(func() error { return a.wrapper.AddAll(results) }, i18n.N("tracks.added", len(results)))
+	}
+}
+
+func (a *App) helpLine() string {
+	if a.focus == queuePane {
+		return i18n.T("tui.help.queue")
+	}
+	return i18n.T("tui.help.search")
+}
+
+func describe(m music.Music, kind music.Kind) string {
+	if kind == music.SongKind {
+		return strings.TrimSpace(fmt.Sprintf("%s - %s (%s)", m.Title, m.Artist, m.Album))
+	}
+	return m.Artist
+}